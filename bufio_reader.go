@@ -0,0 +1,499 @@
+package contextaware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultBufSize           = 4096
+	minReadBufferSize        = 16
+	maxConsecutiveEmptyReads = 100
+)
+
+var errNegativeRead = errors.New("contextaware: reader returned negative count from Read")
+
+// A BufferedReader implements buffering for a contextaware.Reader. It is modeled after bufio.Reader and returns the
+// same errors, such as bufio.ErrBufferFull.
+//
+// If a call is cancelled, nothing is consumed: any data read from the underlying Reader remains buffered, so the call
+// can be retried without losing data. To make this possible the buffer may grow beyond its original size.
+type BufferedReader struct {
+	buf          []byte
+	rd           Reader // reader provided by the client
+	r, w         int    // buf read and write positions
+	err          error
+	lastByte     int // last byte read for UnreadByte; -1 means invalid
+	lastRuneSize int // size of last rune read for UnreadRune; -1 means invalid
+}
+
+// NewBufferedReader returns a new BufferedReader whose buffer has the default size.
+func NewBufferedReader(rd Reader) *BufferedReader {
+	return NewBufferedReaderSize(rd, defaultBufSize)
+}
+
+// NewBufferedReaderSize returns a new BufferedReader whose buffer has at least the specified size. If rd is already a
+// BufferedReader with large enough size, it returns rd.
+func NewBufferedReaderSize(rd Reader, size int) *BufferedReader {
+	if b, ok := rd.(*BufferedReader); ok && len(b.buf) >= size {
+		return b
+	}
+	if size < minReadBufferSize {
+		size = minReadBufferSize
+	}
+	b := new(BufferedReader)
+	b.reset(make([]byte, size), rd)
+	return b
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *BufferedReader) Size() int { return len(b.buf) }
+
+// Reset discards any buffered data, resets all state, and switches the buffered reader to read from r.
+func (b *BufferedReader) Reset(r Reader) {
+	if b == r {
+		return
+	}
+	if b.buf == nil {
+		b.buf = make([]byte, defaultBufSize)
+	}
+	b.reset(b.buf, r)
+}
+
+func (b *BufferedReader) reset(buf []byte, r Reader) {
+	*b = BufferedReader{
+		buf:          buf,
+		rd:           r,
+		lastByte:     -1,
+		lastRuneSize: -1,
+	}
+}
+
+// fill reads a new chunk into the buffer. Errors are recorded in b.err, except for cancellation which is returned
+// so that it only affects the current call.
+func (b *BufferedReader) fill(ctx context.Context) error {
+	// Slide existing data to beginning.
+	if b.r > 0 {
+		copy(b.buf, b.buf[b.r:b.w])
+		b.w -= b.r
+		b.r = 0
+	}
+
+	if b.w >= len(b.buf) {
+		panic("contextaware: tried to fill full buffer")
+	}
+
+	// Read new data: try a limited number of times.
+	for i := maxConsecutiveEmptyReads; i > 0; i-- {
+		n, err := b.rd.ReadContext(ctx, b.buf[b.w:])
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		b.w += n
+		if err != nil {
+			if isCanceled(err) {
+				return err
+			}
+			b.err = err
+			return nil
+		}
+		if n > 0 {
+			return nil
+		}
+	}
+	b.err = io.ErrNoProgress
+	return nil
+}
+
+func (b *BufferedReader) readErr() error {
+	err := b.err
+	b.err = nil
+	return err
+}
+
+// unread puts fragments which have already been consumed back in front of the buffered data, growing the buffer to
+// make room for them.
+func (b *BufferedReader) unread(fragments [][]byte) {
+	n := b.w - b.r
+	for _, f := range fragments {
+		n += len(f)
+	}
+	buf := make([]byte, n)
+	off := 0
+	for _, f := range fragments {
+		off += copy(buf[off:], f)
+	}
+	copy(buf[off:], b.buf[b.r:b.w])
+	b.buf, b.r, b.w = buf, 0, n
+	b.lastByte = -1
+	b.lastRuneSize = -1
+}
+
+// PeekContext returns the next n bytes without advancing the reader. The bytes stop being valid at the next read
+// call. If necessary, PeekContext will read more bytes into the buffer in order to make n bytes available. If
+// PeekContext returns fewer than n bytes, it also returns an error explaining why the read is short. The error is
+// bufio.ErrBufferFull if n is larger than b's buffer size.
+func (b *BufferedReader) PeekContext(ctx context.Context, n int) ([]byte, error) {
+	if n < 0 {
+		return nil, bufio.ErrNegativeCount
+	}
+
+	b.lastByte = -1
+	b.lastRuneSize = -1
+
+	for b.w-b.r < n && b.w-b.r < len(b.buf) && b.err == nil {
+		if err := b.fill(ctx); err != nil {
+			return b.buf[b.r:b.w], err
+		}
+	}
+
+	if n > len(b.buf) {
+		return b.buf[b.r:b.w], bufio.ErrBufferFull
+	}
+
+	// 0 <= n <= len(b.buf)
+	var err error
+	if avail := b.w - b.r; avail < n {
+		// not enough data in buffer
+		n = avail
+		err = b.readErr()
+		if err == nil {
+			err = bufio.ErrBufferFull
+		}
+	}
+	return b.buf[b.r : b.r+n], err
+}
+
+// DiscardContext skips the next n bytes, returning the number of bytes discarded.
+//
+// If DiscardContext skips fewer than n bytes, it also returns an error. If 0 <= n <= b.Buffered(), DiscardContext
+// is guaranteed to succeed without reading from the underlying Reader.
+func (b *BufferedReader) DiscardContext(ctx context.Context, n int) (discarded int, err error) {
+	if n < 0 {
+		return 0, bufio.ErrNegativeCount
+	}
+	if n == 0 {
+		return
+	}
+
+	b.lastByte = -1
+	b.lastRuneSize = -1
+
+	remain := n
+	for {
+		skip := b.Buffered()
+		if skip == 0 {
+			if err := b.fill(ctx); err != nil {
+				return n - remain, err
+			}
+			skip = b.Buffered()
+		}
+		if skip > remain {
+			skip = remain
+		}
+		b.r += skip
+		remain -= skip
+		if remain == 0 {
+			return n, nil
+		}
+		if b.err != nil {
+			return n - remain, b.readErr()
+		}
+	}
+}
+
+// Read reads data into p using the background context.
+func (b *BufferedReader) Read(p []byte) (n int, err error) {
+	return b.ReadContext(context.Background(), p)
+}
+
+// ReadContext reads data into p. It returns the number of bytes read into p. The bytes are taken from at most one
+// ReadContext on the underlying Reader, hence n may be less than len(p).
+func (b *BufferedReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	n = len(p)
+	if n == 0 {
+		if b.Buffered() > 0 {
+			return 0, nil
+		}
+		return 0, b.readErr()
+	}
+	if b.r == b.w {
+		if b.err != nil {
+			return 0, b.readErr()
+		}
+		if len(p) >= len(b.buf) {
+			// Large read, empty buffer.
+			// Read directly into p to avoid copy.
+			n, err = b.rd.ReadContext(ctx, p)
+			if n < 0 {
+				panic(errNegativeRead)
+			}
+			if n > 0 {
+				b.lastByte = int(p[n-1])
+				b.lastRuneSize = -1
+			}
+			return n, err
+		}
+		// One read.
+		// Do not use b.fill, which will loop.
+		b.r = 0
+		b.w = 0
+		n, err = b.rd.ReadContext(ctx, b.buf)
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		if n == 0 {
+			return 0, err
+		}
+		if !isCanceled(err) {
+			// a cancellation which arrived after data was read doesn't affect later calls
+			b.err = err
+		}
+		b.w += n
+	}
+
+	// copy as much as we can
+	n = copy(p, b.buf[b.r:b.w])
+	b.r += n
+	b.lastByte = int(b.buf[b.r-1])
+	b.lastRuneSize = -1
+	return n, nil
+}
+
+// ReadByte reads and returns a single byte using the background context.
+func (b *BufferedReader) ReadByte() (byte, error) {
+	return b.ReadByteContext(context.Background())
+}
+
+// ReadByteContext reads and returns a single byte. If no byte is available, returns an error.
+func (b *BufferedReader) ReadByteContext(ctx context.Context) (byte, error) {
+	b.lastRuneSize = -1
+	for b.r == b.w {
+		if b.err != nil {
+			return 0, b.readErr()
+		}
+		if err := b.fill(ctx); err != nil { // buffer is empty
+			return 0, err
+		}
+	}
+	c := b.buf[b.r]
+	b.r++
+	b.lastByte = int(c)
+	return c, nil
+}
+
+// UnreadByte unreads the last byte. Only the most recently read byte can be unread.
+func (b *BufferedReader) UnreadByte() error {
+	if b.lastByte < 0 || b.r == 0 && b.w > 0 {
+		return bufio.ErrInvalidUnreadByte
+	}
+	// b.r > 0 || b.w == 0
+	if b.r > 0 {
+		b.r--
+	} else {
+		// b.r == 0 && b.w == 0
+		b.w = 1
+	}
+	b.buf[b.r] = byte(b.lastByte)
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	return nil
+}
+
+// ReadRune reads a single UTF-8 encoded Unicode character using the background context.
+func (b *BufferedReader) ReadRune() (r rune, size int, err error) {
+	return b.ReadRuneContext(context.Background())
+}
+
+// ReadRuneContext reads a single UTF-8 encoded Unicode character and returns the rune and its size in bytes. If the
+// encoded rune is invalid, it consumes one byte and returns unicode.ReplacementChar (U+FFFD) with a size of 1.
+func (b *BufferedReader) ReadRuneContext(ctx context.Context) (r rune, size int, err error) {
+	for b.r+utf8.UTFMax > b.w && !utf8.FullRune(b.buf[b.r:b.w]) && b.err == nil && b.w-b.r < len(b.buf) {
+		if err := b.fill(ctx); err != nil { // b.w-b.r < len(buf) => buffer is not full
+			b.lastRuneSize = -1
+			return 0, 0, err
+		}
+	}
+	b.lastRuneSize = -1
+	if b.r == b.w {
+		return 0, 0, b.readErr()
+	}
+	r, size = utf8.DecodeRune(b.buf[b.r:b.w])
+	b.r += size
+	b.lastByte = int(b.buf[b.r-1])
+	b.lastRuneSize = size
+	return r, size, nil
+}
+
+// UnreadRune unreads the last rune. If the most recent method called on the BufferedReader was not a ReadRune,
+// UnreadRune returns an error.
+func (b *BufferedReader) UnreadRune() error {
+	if b.lastRuneSize < 0 || b.r < b.lastRuneSize {
+		return bufio.ErrInvalidUnreadRune
+	}
+	b.r -= b.lastRuneSize
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	return nil
+}
+
+// Buffered returns the number of bytes that can be read from the current buffer.
+func (b *BufferedReader) Buffered() int { return b.w - b.r }
+
+// readSlice reads until the first occurrence of delim in the input, returning a slice pointing at the bytes in the
+// buffer. See bufio.Reader.ReadSlice. If the context is cancelled, nothing is consumed.
+func (b *BufferedReader) readSlice(ctx context.Context, delim byte) (line []byte, err error) {
+	s := 0 // search start index
+	for {
+		// Search buffer.
+		if i := bytes.IndexByte(b.buf[b.r+s:b.w], delim); i >= 0 {
+			i += s
+			line = b.buf[b.r : b.r+i+1]
+			b.r += i + 1
+			break
+		}
+
+		// Pending error?
+		if b.err != nil {
+			line = b.buf[b.r:b.w]
+			b.r = b.w
+			err = b.readErr()
+			break
+		}
+
+		// Buffer full?
+		if b.Buffered() >= len(b.buf) {
+			b.r = b.w
+			line = b.buf
+			err = bufio.ErrBufferFull
+			break
+		}
+
+		s = b.w - b.r // do not rescan area we scanned before
+
+		if err := b.fill(ctx); err != nil { // buffer is not full
+			return nil, err
+		}
+	}
+
+	// Handle last byte, if any.
+	if i := len(line) - 1; i >= 0 {
+		b.lastByte = int(line[i])
+		b.lastRuneSize = -1
+	}
+
+	return
+}
+
+// ReadLineContext is a low-level line-reading primitive. Most callers should use ReadBytesContext('\n') or
+// ReadStringContext('\n') instead or use a Scanner. See bufio.Reader.ReadLine.
+func (b *BufferedReader) ReadLineContext(ctx context.Context) (line []byte, isPrefix bool, err error) {
+	line, err = b.readSlice(ctx, '\n')
+	if err == bufio.ErrBufferFull {
+		// Handle the case where "\r\n" straddles the buffer.
+		if len(line) > 0 && line[len(line)-1] == '\r' {
+			// Put the '\r' back on buf and drop it from line.
+			// Let the next call to ReadLine check for "\r\n".
+			if b.r == 0 {
+				// should be unreachable
+				panic("contextaware: tried to rewind past start of buffer")
+			}
+			b.r--
+			line = line[:len(line)-1]
+		}
+		return line, true, nil
+	}
+
+	if len(line) == 0 {
+		if err != nil {
+			line = nil
+		}
+		return
+	}
+	err = nil
+
+	if line[len(line)-1] == '\n' {
+		drop := 1
+		if len(line) > 1 && line[len(line)-2] == '\r' {
+			drop = 2
+		}
+		line = line[:len(line)-drop]
+	}
+	return
+}
+
+// collectFragments reads until the first occurrence of delim in the input. It returns (slice of full buffers,
+// remaining bytes before delim, total number of bytes in the combined first two elements, error). If the context is
+// cancelled, the full buffers are put back so that nothing is consumed.
+func (b *BufferedReader) collectFragments(
+	ctx context.Context,
+	delim byte,
+) (fullBuffers [][]byte, finalFragment []byte, totalLen int, err error) {
+	var frag []byte
+	// Use readSlice to look for delim, accumulating full buffers.
+	for {
+		var e error
+		frag, e = b.readSlice(ctx, delim)
+		if e == nil { // got final fragment
+			break
+		}
+		if isCanceled(e) {
+			if len(fullBuffers) > 0 {
+				b.unread(fullBuffers)
+			}
+			return nil, nil, 0, e
+		}
+		if e != bufio.ErrBufferFull { // unexpected error
+			err = e
+			break
+		}
+
+		// Make a copy of the buffer.
+		buf := append([]byte(nil), frag...)
+		fullBuffers = append(fullBuffers, buf)
+		totalLen += len(buf)
+	}
+
+	totalLen += len(frag)
+	return fullBuffers, frag, totalLen, err
+}
+
+// ReadBytesContext reads until the first occurrence of delim in the input, returning a slice containing the data up
+// to and including the delimiter. If ReadBytesContext encounters an error before finding a delimiter, it returns the
+// data read before the error and the error itself (often io.EOF). If the context is cancelled, no data is returned
+// and the data read so far remains buffered.
+func (b *BufferedReader) ReadBytesContext(ctx context.Context, delim byte) ([]byte, error) {
+	full, frag, n, err := b.collectFragments(ctx, delim)
+	if isCanceled(err) {
+		return nil, err
+	}
+	// Allocate new buffer to hold the full pieces and the fragment.
+	buf := make([]byte, n)
+	n = 0
+	// Copy full pieces and fragment in.
+	for i := range full {
+		n += copy(buf[n:], full[i])
+	}
+	copy(buf[n:], frag)
+	return buf, err
+}
+
+// ReadStringContext reads until the first occurrence of delim in the input, returning a string containing the data
+// up to and including the delimiter. It behaves like ReadBytesContext.
+func (b *BufferedReader) ReadStringContext(ctx context.Context, delim byte) (string, error) {
+	full, frag, n, err := b.collectFragments(ctx, delim)
+	// Allocate new buffer to hold the full pieces and the fragment.
+	var buf strings.Builder
+	buf.Grow(n)
+	// Copy full pieces and fragment in.
+	for _, fb := range full {
+		buf.Write(fb)
+	}
+	buf.Write(frag)
+	return buf.String(), err
+}
@@ -0,0 +1,226 @@
+package contextaware
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+const startBufSize = 4096 // Size of initial allocation for buffer.
+
+// A Scanner provides a convenient interface for reading data such as a file of newline-delimited lines of text from
+// a contextaware.Reader. It is modeled after bufio.Scanner, uses the same split functions and returns the same errors,
+// such as bufio.ErrTooLong.
+//
+// If a call to ScanContext is cancelled, it returns false and Err reports the cancellation. Unlike other errors,
+// cancellation doesn't stop the scanner: the data read so far remains buffered and scanning resumes with the next
+// call to ScanContext.
+type Scanner struct {
+	r            Reader          // The reader provided by the client.
+	split        bufio.SplitFunc // The function to split the tokens.
+	maxTokenSize int             // Maximum size of a token.
+	token        []byte          // Last token returned by split.
+	buf          []byte          // Buffer used as argument to split.
+	start        int             // First non-processed byte in buf.
+	end          int             // End of data in buf.
+	err          error           // Sticky error.
+	canceled     error           // Error from the last call to ScanContext if it was cancelled.
+	empties      int             // Count of successive empty tokens.
+	scanCalled   bool            // Scan has been called; buffer is in use.
+	done         bool            // Scan has finished.
+}
+
+// NewScanner returns a new Scanner to read from r. The split function defaults to bufio.ScanLines.
+func NewScanner(r Reader) *Scanner {
+	return &Scanner{
+		r:            r,
+		split:        bufio.ScanLines,
+		maxTokenSize: bufio.MaxScanTokenSize,
+	}
+}
+
+// Err returns the first non-EOF error that was encountered by the Scanner. If the last call to ScanContext was
+// cancelled, the cancellation error is returned instead, which satisfies errors.Is(err, context.Canceled) or
+// errors.Is(err, context.DeadlineExceeded).
+func (s *Scanner) Err() error {
+	if s.canceled != nil {
+		return s.canceled
+	}
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Bytes returns the most recent token generated by a call to ScanContext. The underlying array may point to data
+// that will be overwritten by a subsequent call to ScanContext. It does no allocation.
+func (s *Scanner) Bytes() []byte {
+	return s.token
+}
+
+// Text returns the most recent token generated by a call to ScanContext as a newly allocated string holding its
+// bytes.
+func (s *Scanner) Text() string {
+	return string(s.token)
+}
+
+// Scan advances the Scanner to the next token using the background context.
+func (s *Scanner) Scan() bool {
+	return s.ScanContext(context.Background())
+}
+
+// ScanContext advances the Scanner to the next token, which will then be available through the Bytes or Text method.
+// It returns false when the scan stops, either by reaching the end of the input, an error or cancellation. After
+// ScanContext returns false, the Err method will return any error that occurred during scanning, except that if it
+// was io.EOF, Err will return nil.
+func (s *Scanner) ScanContext(ctx context.Context) bool {
+	s.canceled = nil
+	if s.done {
+		return false
+	}
+	s.scanCalled = true
+	// Loop until we have a token.
+	for {
+		// See if we can get a token with what we already have.
+		// If we've run out of data but have an error, give the split function
+		// a chance to recover any remaining, possibly empty token.
+		if s.end > s.start || s.err != nil {
+			advance, token, err := s.split(s.buf[s.start:s.end], s.err != nil)
+			if err != nil {
+				if err == bufio.ErrFinalToken {
+					s.token = token
+					s.done = true
+					// When token is not nil, it means the scanning stops
+					// with a trailing token, and thus the return value
+					// should be true to indicate the existence of the token.
+					return token != nil
+				}
+				s.setErr(err)
+				return false
+			}
+			if !s.advance(advance) {
+				return false
+			}
+			s.token = token
+			if token != nil {
+				if s.err == nil || advance > 0 {
+					s.empties = 0
+				} else {
+					// Returning tokens not advancing input at EOF.
+					s.empties++
+					if s.empties > maxConsecutiveEmptyReads {
+						panic("contextaware.Scan: too many empty tokens without progressing")
+					}
+				}
+				return true
+			}
+		}
+		// We cannot generate a token with what we are holding.
+		// If we've already hit EOF or an I/O error, we are done.
+		if s.err != nil {
+			// Shut it down.
+			s.start = 0
+			s.end = 0
+			return false
+		}
+		// Must read more data.
+		// First, shift data to beginning of buffer if there's lots of empty space
+		// or space is needed.
+		if s.start > 0 && (s.end == len(s.buf) || s.start > len(s.buf)/2) {
+			copy(s.buf, s.buf[s.start:s.end])
+			s.end -= s.start
+			s.start = 0
+		}
+		// Is the buffer full? If so, resize.
+		if s.end == len(s.buf) {
+			// Guarantee no overflow in the multiplication below.
+			const maxInt = int(^uint(0) >> 1)
+			if len(s.buf) >= s.maxTokenSize || len(s.buf) > maxInt/2 {
+				s.setErr(bufio.ErrTooLong)
+				return false
+			}
+			newSize := len(s.buf) * 2
+			if newSize == 0 {
+				newSize = startBufSize
+			}
+			if newSize > s.maxTokenSize {
+				newSize = s.maxTokenSize
+			}
+			newBuf := make([]byte, newSize)
+			copy(newBuf, s.buf[s.start:s.end])
+			s.buf = newBuf
+			s.end -= s.start
+			s.start = 0
+		}
+		// Finally we can read some input. Make sure we don't get stuck with
+		// a misbehaving Reader.
+		for loop := 0; ; {
+			n, err := s.r.ReadContext(ctx, s.buf[s.end:len(s.buf)])
+			if n < 0 || len(s.buf)-s.end < n {
+				s.setErr(bufio.ErrBadReadCount)
+				break
+			}
+			s.end += n
+			if isCanceled(err) {
+				// keep what was read for the next call
+				s.token = nil
+				s.canceled = err
+				return false
+			}
+			if err != nil {
+				s.setErr(err)
+				break
+			}
+			if n > 0 {
+				s.empties = 0
+				break
+			}
+			loop++
+			if loop > maxConsecutiveEmptyReads {
+				s.setErr(io.ErrNoProgress)
+				break
+			}
+		}
+	}
+}
+
+// advance consumes n bytes of the buffer. It reports whether the advance was legal.
+func (s *Scanner) advance(n int) bool {
+	if n < 0 {
+		s.setErr(bufio.ErrNegativeAdvance)
+		return false
+	}
+	if n > s.end-s.start {
+		s.setErr(bufio.ErrAdvanceTooFar)
+		return false
+	}
+	s.start += n
+	return true
+}
+
+// setErr records the first error encountered.
+func (s *Scanner) setErr(err error) {
+	if s.err == nil || s.err == io.EOF {
+		s.err = err
+	}
+}
+
+// Buffer sets the initial buffer to use when scanning and the maximum size of buffer that may be allocated during
+// scanning. The maximum token size must be less than the larger of max and cap(buf). Buffer panics if it is called
+// after scanning has started.
+func (s *Scanner) Buffer(buf []byte, max int) {
+	if s.scanCalled {
+		panic("Buffer called after Scan")
+	}
+	s.buf = buf[0:cap(buf)]
+	s.maxTokenSize = max
+}
+
+// Split sets the split function for the Scanner, such as bufio.ScanWords. Split panics if it is called after scanning
+// has started.
+func (s *Scanner) Split(split bufio.SplitFunc) {
+	if s.scanCalled {
+		panic("Split called after Scan")
+	}
+	s.split = split
+}
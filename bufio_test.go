@@ -0,0 +1,284 @@
+package contextaware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLater writes each chunk to pw in turn, waiting for the previous one to be read.
+func writeLater(pw *PipeWriter, chunks ...string) {
+	go func() {
+		for _, chunk := range chunks {
+			if _, err := pw.Write([]byte(chunk)); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestBufferedReader(t *testing.T) {
+	const text = "hello, 世界\r\nthis is a longer line which doesn't fit in the buffer\nlast"
+
+	t.Run("differential", func(t *testing.T) {
+		readers := map[string]func() io.Reader{
+			"plain":    func() io.Reader { return strings.NewReader(text) },
+			"onebyte":  func() io.Reader { return iotest.OneByteReader(strings.NewReader(text)) },
+			"halfread": func() io.Reader { return iotest.HalfReader(strings.NewReader(text)) },
+			"dataerr":  func() io.Reader { return iotest.DataErrReader(strings.NewReader(text)) },
+		}
+		ops := map[string]func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}){
+			"ReadString": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				s1, err1 := br.ReadStringContext(ctx, '\n')
+				s2, err2 := r.ReadString('\n')
+				return []interface{}{s1, err1}, []interface{}{s2, err2}
+			},
+			"ReadBytes": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				b1, err1 := br.ReadBytesContext(ctx, ' ')
+				b2, err2 := r.ReadBytes(' ')
+				return []interface{}{b1, err1}, []interface{}{b2, err2}
+			},
+			"ReadLine": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				l1, p1, err1 := br.ReadLineContext(ctx)
+				l2, p2, err2 := r.ReadLine()
+				return []interface{}{string(l1), p1, err1}, []interface{}{string(l2), p2, err2}
+			},
+			"ReadRune": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				r1, s1, err1 := br.ReadRuneContext(ctx)
+				r2, s2, err2 := r.ReadRune()
+				return []interface{}{r1, s1, err1}, []interface{}{r2, s2, err2}
+			},
+			"ReadByte": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				c1, err1 := br.ReadByteContext(ctx)
+				c2, err2 := r.ReadByte()
+				return []interface{}{c1, err1}, []interface{}{c2, err2}
+			},
+			"Read": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				p1, p2 := make([]byte, 7), make([]byte, 7)
+				n1, err1 := br.ReadContext(ctx, p1)
+				n2, err2 := r.Read(p2)
+				return []interface{}{p1[:n1], err1}, []interface{}{p2[:n2], err2}
+			},
+			"Peek": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				b1, err1 := br.PeekContext(ctx, 5)
+				b2, err2 := r.Peek(5)
+				return []interface{}{string(b1), err1}, []interface{}{string(b2), err2}
+			},
+			"Discard": func(ctx context.Context, br *BufferedReader, r *bufio.Reader) (interface{}, interface{}) {
+				n1, err1 := br.DiscardContext(ctx, 3)
+				n2, err2 := r.Discard(3)
+				return []interface{}{n1, err1}, []interface{}{n2, err2}
+			},
+		}
+		for rname, newReader := range readers {
+			for oname, op := range ops {
+				t.Run(rname+"/"+oname, func(t *testing.T) {
+					ctx := context.Background()
+					br := NewBufferedReaderSize(NewReader(newReader()), 16)
+					r := bufio.NewReaderSize(newReader(), 16)
+					for i := 0; i < 50; i++ {
+						actual, expected := op(ctx, br, r)
+						require.Equal(t, expected, actual)
+					}
+				})
+			}
+		}
+	})
+	t.Run("retry", func(t *testing.T) {
+		retry := func(t *testing.T, op func(ctx context.Context, br *BufferedReader) (string, error)) {
+			pr, pw := Pipe()
+			br := NewBufferedReaderSize(pr, 16)
+			writeLater(pw, "the first part of a line, ")
+
+			// wait until the first chunk has been buffered
+			_, err := br.PeekContext(context.Background(), 1)
+			require.NoError(t, err)
+
+			ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer clearTimeout()
+			_, err = op(ctx, br)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			writeLater(pw, "and the rest of it\n")
+			s, err := op(context.Background(), br)
+			assert.NoError(t, err)
+			assert.Equal(t, "the first part of a line, and the rest of it\n", s)
+		}
+		t.Run("ReadString", func(t *testing.T) {
+			retry(t, func(ctx context.Context, br *BufferedReader) (string, error) {
+				return br.ReadStringContext(ctx, '\n')
+			})
+		})
+		t.Run("ReadBytes", func(t *testing.T) {
+			retry(t, func(ctx context.Context, br *BufferedReader) (string, error) {
+				b, err := br.ReadBytesContext(ctx, '\n')
+				return string(b), err
+			})
+		})
+	})
+	t.Run("retry byte", func(t *testing.T) {
+		pr, pw := Pipe()
+		br := NewBufferedReader(pr)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := br.ReadByteContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, _, err = br.ReadRuneContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = br.DiscardContext(ctx, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		writeLater(pw, "世界")
+		r, size, err := br.ReadRuneContext(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, '世', r)
+		assert.Equal(t, 3, size)
+		assert.NoError(t, br.UnreadRune())
+		p := make([]byte, 6)
+		_, err = ReadFull(context.Background(), br, p)
+		assert.NoError(t, err)
+		assert.Equal(t, "世界", string(p))
+	})
+}
+
+func TestBufferedWriter(t *testing.T) {
+	t.Run("differential", func(t *testing.T) {
+		var buf1, buf2 bytes.Buffer
+		bw := NewBufferedWriterSize(NewWriter(&buf1), 16)
+		w := bufio.NewWriterSize(&buf2, 16)
+
+		ctx := context.Background()
+		for i := 0; i < 10; i++ {
+			n1, err1 := bw.WriteContext(ctx, []byte("some bytes"))
+			n2, err2 := w.Write([]byte("some bytes"))
+			require.Equal(t, n2, n1)
+			require.Equal(t, err2, err1)
+			n1, err1 = bw.WriteStringContext(ctx, "a string which is longer than the buffer")
+			n2, err2 = w.WriteString("a string which is longer than the buffer")
+			require.Equal(t, n2, n1)
+			require.Equal(t, err2, err1)
+			require.Equal(t, w.WriteByte('!'), bw.WriteByteContext(ctx, '!'))
+			require.NoError(t, bw.FlushContext(ctx))
+			require.NoError(t, w.Flush())
+			require.Equal(t, buf2.String(), buf1.String())
+		}
+		m1, err1 := Copy(ctx, bw, NewReader(strings.NewReader("copied")))
+		m2, err2 := io.Copy(w, strings.NewReader("copied"))
+		require.Equal(t, m2, m1)
+		require.Equal(t, err2, err1)
+		require.NoError(t, bw.FlushContext(ctx))
+		require.NoError(t, w.Flush())
+		assert.Equal(t, buf2.String(), buf1.String())
+	})
+	t.Run("retry", func(t *testing.T) {
+		pr, pw := Pipe()
+		bw := NewBufferedWriterSize(pw, 16)
+
+		n, err := bw.WriteStringContext(context.Background(), "hello, world")
+		require.NoError(t, err)
+		assert.Equal(t, 12, n)
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		err = bw.FlushContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 12, bw.Buffered())
+
+		// a write which needs a flush is partially buffered
+		ctx, clearTimeout = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		n, err = bw.WriteContext(ctx, []byte(" and some more"))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, 16, bw.Buffered())
+
+		go func() {
+			_, _ = bw.WriteContext(context.Background(), []byte(" and some more")[n:])
+			_ = bw.FlushContext(context.Background())
+			_ = pw.Close()
+		}()
+		b, err := ReadAll(context.Background(), pr)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello, world and some more", string(b))
+	})
+	t.Run("sticky", func(t *testing.T) {
+		pr, pw := Pipe()
+		bw := NewBufferedWriterSize(pw, 16)
+		_ = pr.Close()
+
+		_, err := bw.WriteStringContext(context.Background(), "hello")
+		require.NoError(t, err)
+		assert.ErrorIs(t, bw.FlushContext(context.Background()), io.ErrClosedPipe)
+		assert.ErrorIs(t, bw.WriteByteContext(context.Background(), '!'), io.ErrClosedPipe)
+
+		bw.Reset(NewWriter(io.Discard))
+		assert.Equal(t, 0, bw.Buffered())
+		assert.NoError(t, bw.WriteByteContext(context.Background(), '!'))
+	})
+}
+
+func TestScanner(t *testing.T) {
+	const text = "the quick brown\nfox jumps over\r\nthe lazy dog"
+
+	t.Run("differential", func(t *testing.T) {
+		for name, split := range map[string]bufio.SplitFunc{
+			"lines": bufio.ScanLines,
+			"words": bufio.ScanWords,
+			"runes": bufio.ScanRunes,
+		} {
+			t.Run(name, func(t *testing.T) {
+				s1 := NewScanner(NewReader(iotest.OneByteReader(strings.NewReader(text))))
+				s1.Split(split)
+				s2 := bufio.NewScanner(strings.NewReader(text))
+				s2.Split(split)
+				for {
+					ok1, ok2 := s1.ScanContext(context.Background()), s2.Scan()
+					require.Equal(t, ok2, ok1)
+					require.Equal(t, s2.Text(), s1.Text())
+					if !ok1 {
+						break
+					}
+				}
+				assert.NoError(t, s1.Err())
+			})
+		}
+	})
+	t.Run("too long", func(t *testing.T) {
+		s := NewScanner(NewReader(strings.NewReader(text)))
+		s.Buffer(make([]byte, 4), 8)
+		assert.False(t, s.ScanContext(context.Background()))
+		assert.ErrorIs(t, s.Err(), bufio.ErrTooLong)
+	})
+	t.Run("resume", func(t *testing.T) {
+		pr, pw := Pipe()
+		s := NewScanner(pr)
+		writeLater(pw, "first line\nsecond ")
+
+		require.True(t, s.ScanContext(context.Background()))
+		assert.Equal(t, "first line", s.Text())
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		assert.False(t, s.ScanContext(ctx))
+		assert.ErrorIs(t, s.Err(), context.DeadlineExceeded)
+		assert.Nil(t, s.Bytes())
+
+		writeLater(pw, "line\n")
+		require.True(t, s.ScanContext(context.Background()))
+		assert.Equal(t, "second line", s.Text())
+		assert.NoError(t, s.Err())
+
+		_ = pw.Close()
+		assert.False(t, s.ScanContext(context.Background()))
+		assert.NoError(t, s.Err())
+	})
+}
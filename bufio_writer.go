@@ -0,0 +1,263 @@
+package contextaware
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var errNegativeWrite = errors.New("contextaware: writer returned negative count from Write")
+
+// A BufferedWriter implements buffering for a contextaware.Writer. It is modeled after bufio.Writer: if an error
+// occurs writing to a BufferedWriter, no more data will be accepted and all subsequent writes, and FlushContext, will
+// return the error. After all data has been written, the client should call FlushContext to guarantee all data has
+// been forwarded to the underlying Writer.
+//
+// Cancellation is the exception: if a context is cancelled while flushing, the error is only returned by that call
+// and any unflushed bytes remain buffered, so the caller can retry the flush or discard the data by calling Reset.
+type BufferedWriter struct {
+	err error
+	buf []byte
+	n   int
+	wr  Writer
+}
+
+// NewBufferedWriter returns a new BufferedWriter whose buffer has the default size.
+func NewBufferedWriter(w Writer) *BufferedWriter {
+	return NewBufferedWriterSize(w, defaultBufSize)
+}
+
+// NewBufferedWriterSize returns a new BufferedWriter whose buffer has at least the specified size. If w is already a
+// BufferedWriter with large enough size, it returns w.
+func NewBufferedWriterSize(w Writer, size int) *BufferedWriter {
+	if b, ok := w.(*BufferedWriter); ok && len(b.buf) >= size {
+		return b
+	}
+	if size <= 0 {
+		size = defaultBufSize
+	}
+	return &BufferedWriter{
+		buf: make([]byte, size),
+		wr:  w,
+	}
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *BufferedWriter) Size() int { return len(b.buf) }
+
+// Reset discards any unflushed buffered data, clears any error, and resets b to write its output to w.
+func (b *BufferedWriter) Reset(w Writer) {
+	if b == w {
+		return
+	}
+	if b.buf == nil {
+		b.buf = make([]byte, defaultBufSize)
+	}
+	b.err = nil
+	b.n = 0
+	b.wr = w
+}
+
+// setErr records a sticky error. Cancellation only affects the current call, so it isn't recorded.
+func (b *BufferedWriter) setErr(err error) {
+	if !isCanceled(err) {
+		b.err = err
+	}
+}
+
+// Flush writes any buffered data to the underlying Writer using the background context.
+func (b *BufferedWriter) Flush() error {
+	return b.FlushContext(context.Background())
+}
+
+// FlushContext writes any buffered data to the underlying Writer. If the context is cancelled, the bytes which
+// weren't written remain buffered.
+func (b *BufferedWriter) FlushContext(ctx context.Context) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.n == 0 {
+		return nil
+	}
+	n, err := b.wr.WriteContext(ctx, b.buf[0:b.n])
+	if n < b.n && err == nil {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		if n > 0 && n < b.n {
+			copy(b.buf[0:b.n-n], b.buf[n:b.n])
+		}
+		b.n -= n
+		b.setErr(err)
+		return err
+	}
+	b.n = 0
+	return nil
+}
+
+// Available returns how many bytes are unused in the buffer.
+func (b *BufferedWriter) Available() int { return len(b.buf) - b.n }
+
+// AvailableBuffer returns an empty buffer with b.Available() capacity. This buffer is intended to be appended to and
+// passed to an immediately succeeding WriteContext call. The buffer is only valid until the next write operation on
+// b.
+func (b *BufferedWriter) AvailableBuffer() []byte {
+	return b.buf[b.n:][:0]
+}
+
+// Buffered returns the number of bytes that have been written into the current buffer.
+func (b *BufferedWriter) Buffered() int { return b.n }
+
+// Write writes the contents of p into the buffer using the background context.
+func (b *BufferedWriter) Write(p []byte) (nn int, err error) {
+	return b.WriteContext(context.Background(), p)
+}
+
+// WriteContext writes the contents of p into the buffer. It returns the number of bytes written, which includes the
+// bytes that were buffered. If nn < len(p), it also returns an error explaining why the write is short.
+func (b *BufferedWriter) WriteContext(ctx context.Context, p []byte) (nn int, err error) {
+	for len(p) > b.Available() && b.err == nil {
+		var n int
+		if b.Buffered() == 0 {
+			// Large write, empty buffer.
+			// Write directly from p to avoid copy.
+			n, err = b.wr.WriteContext(ctx, p)
+			if n < 0 {
+				panic(errNegativeWrite)
+			}
+			b.setErr(err)
+		} else {
+			n = copy(b.buf[b.n:], p)
+			b.n += n
+			err = b.FlushContext(ctx)
+		}
+		nn += n
+		p = p[n:]
+		if isCanceled(err) {
+			return nn, err
+		}
+	}
+	if b.err != nil {
+		return nn, b.err
+	}
+	n := copy(b.buf[b.n:], p)
+	b.n += n
+	nn += n
+	return nn, nil
+}
+
+// WriteByte writes a single byte using the background context.
+func (b *BufferedWriter) WriteByte(c byte) error {
+	return b.WriteByteContext(context.Background(), c)
+}
+
+// WriteByteContext writes a single byte.
+func (b *BufferedWriter) WriteByteContext(ctx context.Context, c byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.Available() <= 0 {
+		if err := b.FlushContext(ctx); err != nil {
+			return err
+		}
+	}
+	b.buf[b.n] = c
+	b.n++
+	return nil
+}
+
+// WriteString writes a string using the background context.
+func (b *BufferedWriter) WriteString(s string) (int, error) {
+	return b.WriteStringContext(context.Background(), s)
+}
+
+// WriteStringContext writes a string. It returns the number of bytes written. If the count is less than len(s), it
+// also returns an error explaining why the write is short.
+func (b *BufferedWriter) WriteStringContext(ctx context.Context, s string) (nn int, err error) {
+	sw, tryStringWriter := b.wr.(StringWriter)
+
+	for len(s) > b.Available() && b.err == nil {
+		var n int
+		if b.Buffered() == 0 && tryStringWriter {
+			// Large write, empty buffer, and the underlying writer supports
+			// WriteStringContext: forward the write to the underlying StringWriter.
+			// This avoids an extra copy.
+			n, err = sw.WriteStringContext(ctx, s)
+			b.setErr(err)
+		} else {
+			n = copy(b.buf[b.n:], s)
+			b.n += n
+			err = b.FlushContext(ctx)
+		}
+		nn += n
+		s = s[n:]
+		if isCanceled(err) {
+			return nn, err
+		}
+	}
+	if b.err != nil {
+		return nn, b.err
+	}
+	n := copy(b.buf[b.n:], s)
+	b.n += n
+	nn += n
+	return nn, nil
+}
+
+// ReadFrom reads data from r until EOF using the background context. It implements io.ReaderFrom.
+func (b *BufferedWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	cr, ok := r.(Reader)
+	if !ok {
+		cr = NewReader(r)
+	}
+	return b.ReadFromContext(context.Background(), cr)
+}
+
+// ReadFromContext reads data from r until EOF. If the underlying writer is a ReaderFrom, this calls its
+// ReadFromContext. If there is buffered data and an underlying ReadFromContext, this fills the buffer and writes it
+// before calling ReadFromContext.
+func (b *BufferedWriter) ReadFromContext(ctx context.Context, r Reader) (n int64, err error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	readerFrom, readerFromOK := b.wr.(ReaderFrom)
+	var m int
+	for {
+		if b.Available() == 0 {
+			if err1 := b.FlushContext(ctx); err1 != nil {
+				return n, err1
+			}
+		}
+		if readerFromOK && b.Buffered() == 0 {
+			nn, err := readerFrom.ReadFromContext(ctx, r)
+			b.setErr(err)
+			n += nn
+			return n, err
+		}
+		nr := 0
+		for nr < maxConsecutiveEmptyReads {
+			m, err = r.ReadContext(ctx, b.buf[b.n:])
+			if m != 0 || err != nil {
+				break
+			}
+			nr++
+		}
+		if nr == maxConsecutiveEmptyReads {
+			return n, io.ErrNoProgress
+		}
+		b.n += m
+		n += int64(m)
+		if err != nil {
+			break
+		}
+	}
+	if err == io.EOF {
+		// If we filled the buffer exactly, flush preemptively.
+		if b.Available() == 0 {
+			err = b.FlushContext(ctx)
+		} else {
+			err = nil
+		}
+	}
+	return n, err
+}
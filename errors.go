@@ -0,0 +1,69 @@
+package contextaware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// A CanceledError is returned when an operation is interrupted because its context was cancelled or its deadline
+// was exceeded. It unwraps to the error returned by the interrupted operation, and also matches the context's error
+// and the cause of the cancellation, so errors.Is(err, context.Canceled) and errors.As(err, &opErr) both work as
+// expected. The cause is only reported when built with Go 1.20 or later, which added context.Cause.
+type CanceledError struct {
+	Op      string // the operation which was interrupted, e.g. "read", "write" or "lock"
+	N       int64  // the number of bytes transferred before the operation was interrupted
+	Context error  // the context's error, either context.Canceled or context.DeadlineExceeded
+	Cause   error  // the cause of the cancellation, if it differs from the context's error
+	Err     error  // the error returned by the interrupted operation, if any
+}
+
+func (e *CanceledError) Error() string {
+	s := "contextaware: " + e.Op + " canceled"
+	if e.N > 0 {
+		s += " after " + strconv.FormatInt(e.N, 10) + " bytes"
+	}
+	s += ": " + e.Context.Error()
+	if e.Cause != nil {
+		s += " (" + e.Cause.Error() + ")"
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Unwrap returns the error returned by the interrupted operation.
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the context's error or the cause of the cancellation, so that errors.Is matches them
+// as well as the errors in the chain of Err.
+func (e *CanceledError) Is(target error) bool {
+	return errors.Is(e.Context, target) || (e.Cause != nil && errors.Is(e.Cause, target))
+}
+
+// Timeout reports whether the operation was interrupted by a deadline, so that os.IsTimeout can be used.
+func (e *CanceledError) Timeout() bool {
+	return errors.Is(e.Context, context.DeadlineExceeded)
+}
+
+// canceled returns a new CanceledError for an operation which was interrupted by the context.
+func canceled(ctx context.Context, op string, n int, err error) error {
+	return &CanceledError{Op: op, N: int64(n), Context: ctx.Err(), Cause: causeOf(ctx), Err: err}
+}
+
+// annotate records the operation and the number of bytes transferred on a CanceledError returned by one of the
+// withCancelVia functions. Other errors are returned unchanged.
+func annotate(err error, op string, n int64) error {
+	if ce, ok := err.(*CanceledError); ok && ce.Op == "" {
+		ce.Op, ce.N = op, n
+	}
+	return err
+}
+
+// isCanceled returns true if err reports that an operation was interrupted by its context.
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
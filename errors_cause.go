@@ -0,0 +1,15 @@
+//go:build go1.20
+// +build go1.20
+
+package contextaware
+
+import "context"
+
+// causeOf returns the cause of the context's cancellation, as reported by context.Cause, if it differs from the
+// context's error.
+func causeOf(ctx context.Context) error {
+	if cause := context.Cause(ctx); cause != ctx.Err() {
+		return cause
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build go1.20
+// +build go1.20
+
+package contextaware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanceledErrorCause(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+
+	c1, c2 := tcpPair(t)
+	defer c1.Close()
+	defer c2.Close()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(50*time.Millisecond, func() { cancel(errShutdown) })
+
+	_, err := NewReader(c2).ReadContext(ctx, make([]byte, 4))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errShutdown)
+	assert.Contains(t, err.Error(), "shutting down")
+
+	pr, pw := Pipe()
+	_, err = pr.ReadContext(ctx, make([]byte, 4))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errShutdown)
+	_, err = pw.WriteContext(ctx, make([]byte, 4))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errShutdown)
+
+	var m Mutex
+	m.Lock()
+	err = m.LockContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, errShutdown)
+	assert.Equal(t, "contextaware: lock canceled: context canceled (shutting down)", err.Error())
+}
@@ -0,0 +1,11 @@
+//go:build !go1.20
+// +build !go1.20
+
+package contextaware
+
+import "context"
+
+// causeOf returns nil, since context.Cause requires Go 1.20.
+func causeOf(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package contextaware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanceledError(t *testing.T) {
+	t.Run("deadline", func(t *testing.T) {
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+		defer c2.Close()
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+
+		_, err := NewReader(c2).ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.True(t, os.IsTimeout(err))
+
+		var opErr *net.OpError
+		assert.True(t, errors.As(err, &opErr))
+
+		var ce *CanceledError
+		if assert.True(t, errors.As(err, &ce)) {
+			assert.Equal(t, "read", ce.Op)
+			assert.Equal(t, context.DeadlineExceeded, ce.Context)
+		}
+	})
+	t.Run("cancel", func(t *testing.T) {
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+		defer c2.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		_, err := NewReader(c2).ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, os.IsTimeout(err))
+
+		var opErr *net.OpError
+		assert.True(t, errors.As(err, &opErr))
+	})
+	t.Run("bytes", func(t *testing.T) {
+		pr, pw := Pipe()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = pr.ReadContext(ctx, make([]byte, 3))
+			cancel()
+		}()
+
+		n, err := pw.WriteContext(ctx, []byte{1, 2, 3, 4, 5})
+		wg.Wait()
+		assert.Equal(t, 3, n)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		var ce *CanceledError
+		if assert.True(t, errors.As(err, &ce)) {
+			assert.Equal(t, "write", ce.Op)
+			assert.Equal(t, int64(3), ce.N)
+			assert.Nil(t, ce.Err)
+		}
+		assert.Equal(t, "contextaware: write canceled after 3 bytes: context canceled", err.Error())
+	})
+}
+
+func tcpPair(t *testing.T) (net.Conn, net.Conn) {
+	li, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer li.Close()
+
+	c1, err := net.Dial("tcp", li.Addr().String())
+	require.NoError(t, err)
+	c2, err := li.Accept()
+	require.NoError(t, err)
+	return c1, c2
+}
@@ -12,11 +12,11 @@ func main() {
 		{TypeIn: "io.Closer", TypeOut: "io.Closer"},
 		{TypeIn: "io.Reader", TypeOut: "Reader", Function: "wrapReader"},
 		{TypeIn: "io.ReaderAt", TypeOut: "ReaderAt", Function: "wrapReaderAt"},
-		{TypeIn: "io.ReaderFrom", TypeOut: "io.ReaderFrom"},
+		{TypeIn: "io.ReaderFrom", TypeOut: "ReaderFrom", Function: "wrapReaderFrom"},
 		{TypeIn: "io.Seeker", TypeOut: "io.Seeker"},
 		{TypeIn: "io.Writer", TypeOut: "Writer", Function: "wrapWriter"},
 		{TypeIn: "io.WriterAt", TypeOut: "WriterAt", Function: "wrapWriterAt"},
-		{TypeIn: "io.WriterTo", TypeOut: "io.WriterTo"},
+		{TypeIn: "io.WriterTo", TypeOut: "WriterTo", Function: "wrapWriterTo"},
 	}
 
 	fmt.Print(`// Code generated by generate-wrap; DO NOT EDIT.
@@ -26,7 +26,7 @@ import (
 	"io"
 )
 
-func wrapIO(i interface{}) interface{} {
+func wrapIO(i interface{}, o *options) (interface{}, error) {
 `)
 	fmt.Printf("\ttype (\n")
 	for i, c := range converters {
@@ -45,46 +45,43 @@ func wrapIO(i interface{}) interface{} {
 	}
 	fmt.Printf("\t)\n\n")
 	fmt.Printf("\tvar f uint64\n")
+	fmt.Printf("\tvar err error\n")
+	fmt.Printf("\tw := wrapped{obj: i, opts: o}\n")
 
-	for i := range converters {
-		fmt.Printf("\to%02d, b%02d := i.(t%02di)\n", i, i, i)
-		fmt.Printf("\tif b%02d { f |= 0x%04x }\n", i, pow2(i))
+	// converted interfaces may be dropped by their wrap function returning nil
+	for i, c := range converters {
+		if c.TypeIn == c.TypeOut {
+			fmt.Printf("\to%02d, b%02d := i.(t%02di)\n", i, i, i)
+			fmt.Printf("\tif b%02d { f |= 0x%04x }\n", i, pow2(i))
+		} else {
+			fmt.Printf("\tvar o%02d t%02do\n", i, i)
+			fmt.Printf("\tif v, ok := i.(t%02di); ok {\n", i)
+			fmt.Printf("\t\tif o%02d, err = f%02d(v, o); err != nil { return nil, err }\n", i, i)
+			fmt.Printf("\t\tif o%02d != nil { f |= 0x%04x; w.add(o%02d) }\n", i, pow2(i), i)
+			fmt.Printf("\t}\n")
+		}
 	}
 	fmt.Printf("\n")
 
 	fmt.Printf("\tswitch f {\n")
 	for i := 0; i < pow2(len(converters)); i++ {
 		fmt.Printf("\tcase 0x%04x: return struct{", i)
-		addSep := false
 		for j, c := range converters {
 			if pow2(j)&i > 0 {
-				if addSep {
-					fmt.Printf(";")
-				}
 				if c.TypeIn == c.TypeOut {
-					fmt.Printf("t%02di", j)
+					fmt.Printf("t%02di;", j)
 				} else {
-					fmt.Printf("t%02do", j)
+					fmt.Printf("t%02do;", j)
 				}
-				addSep = true
 			}
 		}
-		fmt.Printf("}{")
-		addSep = false
-		for j, c := range converters {
+		fmt.Printf("wrapped}{")
+		for j := range converters {
 			if pow2(j)&i > 0 {
-				if addSep {
-					fmt.Printf(",")
-				}
-				if c.TypeIn == c.TypeOut {
-					fmt.Printf("o%02d", j)
-				} else {
-					fmt.Printf("f%02d(o%02d)", j, j)
-				}
-				addSep = true
+				fmt.Printf("o%02d,", j)
 			}
 		}
-		fmt.Printf("}\n")
+		fmt.Printf("w}, nil\n")
 	}
 	fmt.Printf("\t}\n\n")
 
@@ -6,7 +6,119 @@ import (
 	"io"
 )
 
-var errInvalidWrite = errors.New("invalid write result")
+var (
+	errInvalidRead  = errors.New("invalid read result")
+	errInvalidWrite = errors.New("invalid write result")
+)
+
+// defaultChunkSize is the default size of the chunks used by NewChunkedReader and NewChunkedWriter.
+const defaultChunkSize = 32 * 1024
+
+// A StringWriter is an io.StringWriter that also supports cancellation via a context.Context.
+type StringWriter interface {
+	io.StringWriter
+	WriteStringContext(ctx context.Context, s string) (n int, err error)
+}
+
+// WriteString writes the contents of the string s to w, which accepts a slice of bytes.
+// If w implements StringWriter, its WriteStringContext method is invoked directly.
+// Otherwise, w.WriteContext is called exactly once.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Writers.
+func WriteString(ctx context.Context, w Writer, s string) (n int, err error) {
+	if sw, ok := w.(StringWriter); ok {
+		return sw.WriteStringContext(ctx, s)
+	}
+	return w.WriteContext(ctx, []byte(s))
+}
+
+// ReadAtLeast reads from r into buf until it has read at least min bytes.
+// It returns the number of bytes copied and an error if fewer bytes were read.
+// The error is EOF only if no bytes were read.
+// If an EOF happens after reading fewer than min bytes,
+// ReadAtLeast returns ErrUnexpectedEOF.
+// If min is greater than the length of buf, ReadAtLeast returns ErrShortBuffer.
+// On return, n >= min if and only if err == nil.
+// If r returns an error having read at least min bytes, the error is dropped.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers.
+func ReadAtLeast(ctx context.Context, r Reader, buf []byte, min int) (n int, err error) {
+	if len(buf) < min {
+		return 0, io.ErrShortBuffer
+	}
+	for n < min && err == nil {
+		var nn int
+		nn, err = r.ReadContext(ctx, buf[n:])
+		n += nn
+	}
+	if n >= min {
+		err = nil
+	} else if n > 0 && err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
+
+// ReadFull reads exactly len(buf) bytes from r into buf.
+// It returns the number of bytes copied and an error if fewer bytes were read.
+// The error is EOF only if no bytes were read.
+// If an EOF happens after reading some but not all the bytes,
+// ReadFull returns ErrUnexpectedEOF.
+// On return, n == len(buf) if and only if err == nil.
+// If r returns an error having read at least len(buf) bytes, the error is dropped.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers.
+func ReadFull(ctx context.Context, r Reader, buf []byte) (n int, err error) {
+	return ReadAtLeast(ctx, r, buf, len(buf))
+}
+
+// ReadAll reads from r until an error or EOF and returns the data it read.
+// A successful call returns err == nil, not err == EOF. Because ReadAll is
+// defined to read from src until EOF, it does not treat an EOF from Read
+// as an error to be reported.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers.
+func ReadAll(ctx context.Context, r Reader) ([]byte, error) {
+	b := make([]byte, 0, 512)
+	for {
+		n, err := r.ReadContext(ctx, b[len(b):cap(b)])
+		b = b[:len(b)+n]
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return b, err
+		}
+
+		if len(b) == cap(b) {
+			// Add more capacity (let append pick how much).
+			b = append(b, 0)[:len(b)]
+		}
+	}
+}
+
+// CopyN copies n bytes (or until an error) from src to dst.
+// It returns the number of bytes copied and the earliest
+// error encountered while copying.
+// On return, written == n if and only if err == nil.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers and Writers.
+func CopyN(ctx context.Context, dst Writer, src Reader, n int64) (written int64, err error) {
+	written, err = Copy(ctx, dst, LimitReader(src, n))
+	if written == n {
+		return n, nil
+	}
+	if written < n && err == nil {
+		// src stopped early; must have been EOF.
+		err = io.EOF
+	}
+	return
+}
 
 // Copy copies from src to dst until either EOF is reached
 // on src or an error occurs. It returns the number of bytes
@@ -18,18 +130,55 @@ var errInvalidWrite = errors.New("invalid write result")
 //
 // This is copied from the stdlib and modified to use contextaware
 // Readers and Writers.
+//
+// If src implements the WriterTo interface, the copy is implemented by calling src.WriteToContext(ctx, dst).
+// Otherwise, if dst implements the ReaderFrom interface, the copy is implemented by calling
+// dst.ReadFromContext(ctx, src).
 func Copy(ctx context.Context, dst Writer, src Reader) (int64, error) {
 	return copyBuffer(ctx, dst, src, nil)
 }
 
+// CopyBuffer is identical to Copy except that it stages through the
+// provided buffer (if one is required) rather than allocating a
+// temporary one. If buf is nil, one is allocated; otherwise if it has
+// zero length, CopyBuffer panics.
+//
+// If either src implements WriterTo or dst implements ReaderFrom,
+// buf will not be used to perform the copy.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers and Writers.
+func CopyBuffer(ctx context.Context, dst Writer, src Reader, buf []byte) (written int64, err error) {
+	if buf != nil && len(buf) == 0 {
+		panic("empty buffer in CopyBuffer")
+	}
+	return copyBuffer(ctx, dst, src, buf)
+}
+
 // copyBuffer is the actual implementation of Copy and CopyBuffer.
 // if buf is nil, one is allocated.
 //
 // This is copied from the stdlib and modified to use contextaware
 // Readers and Writers.
 func copyBuffer(ctx context.Context, dst Writer, src Reader, buf []byte) (written int64, err error) {
+	// If the reader has a WriteToContext method, use it to do the copy.
+	// Avoids an allocation and a copy.
+	if wt, ok := src.(WriterTo); ok {
+		return wt.WriteToContext(ctx, dst)
+	}
+	// Similarly, if the writer has a ReadFromContext method, use it to do the copy.
+	if rf, ok := dst.(ReaderFrom); ok {
+		return rf.ReadFromContext(ctx, src)
+	}
 	if buf == nil {
 		size := 32 * 1024
+		if l, ok := src.(*LimitedReader); ok && int64(size) > l.N {
+			if l.N < 1 {
+				size = 1
+			} else {
+				size = int(l.N)
+			}
+		}
 		buf = make([]byte, size)
 	}
 	for {
@@ -61,3 +210,176 @@ func copyBuffer(ctx context.Context, dst Writer, src Reader, buf []byte) (writte
 	}
 	return written, err
 }
+
+// LimitReader returns a Reader that reads from r
+// but stops with EOF after n bytes.
+// The underlying implementation is a *LimitedReader.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers.
+func LimitReader(r Reader, n int64) Reader { return &LimitedReader{r, n} }
+
+// A LimitedReader reads from R but limits the amount of
+// data returned to just N bytes. Each call to ReadContext
+// updates N to reflect the new amount remaining.
+// ReadContext returns EOF when N <= 0 or when the underlying R returns EOF.
+type LimitedReader struct {
+	R Reader // underlying reader
+	N int64  // max bytes remaining
+}
+
+func (l *LimitedReader) Read(p []byte) (n int, err error) {
+	return l.ReadContext(context.Background(), p)
+}
+
+func (l *LimitedReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	if l.N <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.N {
+		p = p[0:l.N]
+	}
+	n, err = l.R.ReadContext(ctx, p)
+	l.N -= int64(n)
+	return
+}
+
+// NewSectionReader returns a SectionReader that reads from r
+// starting at offset off and stops with EOF after n bytes.
+//
+// This is copied from the stdlib and modified to use contextaware
+// ReaderAts.
+func NewSectionReader(r ReaderAt, off int64, n int64) *SectionReader {
+	var remaining int64
+	const maxint64 = 1<<63 - 1
+	if off <= maxint64-n {
+		remaining = n + off
+	} else {
+		// Overflow, with no way to return error.
+		// Assume we can read up to an offset of 1<<63 - 1.
+		remaining = maxint64
+	}
+	return &SectionReader{r, off, off, remaining}
+}
+
+// SectionReader implements Read, Seek, and ReadAt on a section
+// of an underlying ReaderAt, along with their context-aware
+// equivalents.
+type SectionReader struct {
+	r     ReaderAt
+	base  int64
+	off   int64
+	limit int64
+}
+
+func (s *SectionReader) Read(p []byte) (n int, err error) {
+	return s.ReadContext(context.Background(), p)
+}
+
+func (s *SectionReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	if s.off >= s.limit {
+		return 0, io.EOF
+	}
+	if max := s.limit - s.off; int64(len(p)) > max {
+		p = p[0:max]
+	}
+	n, err = s.r.ReadAtContext(ctx, p, s.off)
+	s.off += int64(n)
+	return
+}
+
+var errWhence = errors.New("Seek: invalid whence")
+var errOffset = errors.New("Seek: invalid offset")
+
+func (s *SectionReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errWhence
+	case io.SeekStart:
+		offset += s.base
+	case io.SeekCurrent:
+		offset += s.off
+	case io.SeekEnd:
+		offset += s.limit
+	}
+	if offset < s.base {
+		return 0, errOffset
+	}
+	s.off = offset
+	return offset - s.base, nil
+}
+
+func (s *SectionReader) ReadAt(p []byte, off int64) (n int, err error) {
+	return s.ReadAtContext(context.Background(), p, off)
+}
+
+func (s *SectionReader) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	if off < 0 || off >= s.Size() {
+		return 0, io.EOF
+	}
+	off += s.base
+	if max := s.limit - off; int64(len(p)) > max {
+		p = p[0:max]
+		n, err = s.r.ReadAtContext(ctx, p, off)
+		if err == nil {
+			err = io.EOF
+		}
+		return n, err
+	}
+	return s.r.ReadAtContext(ctx, p, off)
+}
+
+// Size returns the size of the section in bytes.
+func (s *SectionReader) Size() int64 { return s.limit - s.base }
+
+// TeeReader returns a Reader that writes to w what it reads from r.
+// All reads from r performed through it are matched with
+// corresponding writes to w, using the same context. There is no
+// internal buffering - the write must complete before the read
+// completes. Any error encountered while writing is reported as a
+// read error.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers and Writers.
+func TeeReader(r Reader, w Writer) Reader {
+	return &teeReader{r, w}
+}
+
+type teeReader struct {
+	r Reader
+	w Writer
+}
+
+func (t *teeReader) Read(p []byte) (n int, err error) {
+	return t.ReadContext(context.Background(), p)
+}
+
+func (t *teeReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	n, err = t.r.ReadContext(ctx, p)
+	if n > 0 {
+		if n, err := t.w.WriteContext(ctx, p[:n]); err != nil {
+			return n, err
+		}
+	}
+	return
+}
+
+// contextReader adapts a contextaware.Reader to an io.Reader by binding it to a context.
+type contextReader struct {
+	ctx context.Context
+	r   Reader
+}
+
+func (r contextReader) Read(p []byte) (n int, err error) {
+	return r.r.ReadContext(r.ctx, p)
+}
+
+// contextWriter adapts a contextaware.Writer to an io.Writer by binding it to a context.
+type contextWriter struct {
+	ctx context.Context
+	w   Writer
+}
+
+func (w contextWriter) Write(p []byte) (n int, err error) {
+	return w.w.WriteContext(w.ctx, p)
+}
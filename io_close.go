@@ -0,0 +1,117 @@
+package contextaware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrClosedByCancel is returned by operations on an object which was closed by StrategyClose because a context was
+// cancelled while a previous operation was in progress.
+var ErrClosedByCancel = errors.New("contextaware: closed due to cancellation")
+
+// closeOnCancel closes an object when a context is cancelled during an operation. It is shared by all the interfaces
+// wrapped for a single object, so once the object has been closed every subsequent operation fails, as do any
+// concurrent operations it interrupted.
+type closeOnCancel struct {
+	closer io.Closer
+
+	mu     sync.Mutex
+	closed bool // set before the object is closed, so interrupted operations can tell why
+}
+
+func (c *closeOnCancel) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *closeOnCancel) close() {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	_ = c.closer.Close()
+}
+
+func (c *closeOnCancel) withCancelViaClose(
+	ctx context.Context,
+	operation func() error,
+) (err error) {
+	if c.isClosed() {
+		return ErrClosedByCancel
+	}
+
+	// fail early
+	select {
+	case <-ctx.Done():
+		return canceled(ctx, "", 0, nil)
+	default:
+	}
+
+	var cancelOnce sync.Once
+
+	if done := ctx.Done(); done != nil {
+		doneCtx, doneCancel := context.WithCancel(ctx)
+		defer doneCancel()
+
+		go func() {
+			<-doneCtx.Done()
+			cancelOnce.Do(c.close)
+		}()
+	}
+
+	// run the operation
+	err = operation()
+
+	// Clear any pending cancellation, waiting for a Close that is already in progress to complete. See
+	// withCancelViaDeadline for details.
+	cancelOnce.Do(func() {})
+
+	switch {
+	case !c.isClosed():
+		return err
+	case ctx.Err() != nil:
+		return canceled(ctx, "", 0, err)
+	case err != nil:
+		// the object was closed by the cancellation of a concurrent operation
+		return ErrClosedByCancel
+	default:
+		return nil
+	}
+}
+
+type readerViaClose struct {
+	io.Reader
+	c *closeOnCancel
+}
+
+func (r readerViaClose) strategy() Strategy {
+	return StrategyClose
+}
+
+func (r readerViaClose) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	err = r.c.withCancelViaClose(ctx, func() (err error) {
+		n, err = r.Read(p)
+		return err
+	})
+	return n, annotate(err, "read", int64(n))
+}
+
+type writerViaClose struct {
+	io.Writer
+	c *closeOnCancel
+}
+
+func (w writerViaClose) strategy() Strategy {
+	return StrategyClose
+}
+
+func (w writerViaClose) WriteContext(ctx context.Context, p []byte) (n int, err error) {
+	err = w.c.withCancelViaClose(ctx, func() (err error) {
+		n, err = w.Write(p)
+		return err
+	})
+	return n, annotate(err, "write", int64(n))
+}
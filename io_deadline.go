@@ -3,7 +3,6 @@ package contextaware
 import (
 	"context"
 	"errors"
-	"fmt"
 	"os"
 	"sync"
 	"time"
@@ -21,104 +20,518 @@ type (
 	}
 )
 
+// supportsSetDeadline reports whether obj implements SetDeadline. It doesn't call it, since that would clear any
+// deadline the caller has already set: objects whose SetDeadline returns os.ErrNoDeadline are detected when the
+// first deadline is applied.
 func supportsSetDeadline(obj interface{}) (withSetDeadline, bool) {
 	wsd, ok := obj.(withSetDeadline)
-	if !ok {
-		return nil, false
-	}
-
-	err := wsd.SetDeadline(time.Time{})
-	return wsd, !errors.Is(err, os.ErrNoDeadline)
+	return wsd, ok
 }
 
+// supportsSetReadDeadline reports whether obj implements SetReadDeadline, see supportsSetDeadline.
 func supportsSetReadDeadline(obj interface{}) (withSetReadDeadline, bool) {
 	wsrd, ok := obj.(withSetReadDeadline)
+	return wsrd, ok
+}
+
+// supportsSetWriteDeadline reports whether obj implements SetWriteDeadline, see supportsSetDeadline.
+func supportsSetWriteDeadline(obj interface{}) (withSetWriteDeadline, bool) {
+	wswd, ok := obj.(withSetWriteDeadline)
+	return wswd, ok
+}
+
+// newDeadlines creates the deadlines used to cancel reads and writes on obj. Either is nil if the object doesn't
+// implement deadlines for that direction. Objects which only implement SetDeadline share a single deadline between
+// both directions via a deadlineCoordinator. Objects which implement the setter for only one direction fall back
+// to SetDeadline for the other.
+func newDeadlines(obj interface{}) (rd, wd *deadline) {
+	if wsrd, ok := supportsSetReadDeadline(obj); ok {
+		rd = newDeadline(wsrd.SetReadDeadline)
+	}
+	if wswd, ok := supportsSetWriteDeadline(obj); ok {
+		wd = newDeadline(wswd.SetWriteDeadline)
+	}
+	if rd != nil && wd != nil {
+		return rd, wd
+	}
+	wsd, ok := supportsSetDeadline(obj)
+	switch {
+	case !ok:
+	case rd == nil && wd == nil:
+		c := &deadlineCoordinator{setDeadline: wsd.SetDeadline, changed: make(chan struct{})}
+		rd, wd = c.newDeadline(0), c.newDeadline(1)
+	case rd == nil:
+		rd = newDeadline(wsd.SetDeadline)
+	default:
+		wd = newDeadline(wsd.SetDeadline)
+	}
+	return rd, wd
+}
+
+// A deadlineCoordinator shares the single deadline of an object which only implements SetDeadline between reads and
+// writes. Only directions with an operation in progress contribute to the object's deadline, which is the earlier
+// of the two. Since cancelling one direction necessarily interrupts the other, withCancelViaDeadline retries a
+// single-shot operation which was interrupted by the other direction once that direction's deadline has been
+// cleared.
+type deadlineCoordinator struct {
+	setDeadline func(time.Time) error
+
+	mu        sync.Mutex    // guards following
+	deadlines [2]time.Time  // the deadline of each direction
+	changed   chan struct{} // closed when a deadline changes
+}
+
+func (c *deadlineCoordinator) newDeadline(dir int) *deadline {
+	d := newDeadline(func(t time.Time) error {
+		return c.set(dir, t)
+	})
+	d.coordinator, d.dir = c, dir
+	return d
+}
+
+func (c *deadlineCoordinator) set(dir int, t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deadlines[dir] = t
+	close(c.changed)
+	c.changed = make(chan struct{})
+	return c.setDeadline(earliest(c.deadlines[0], c.deadlines[1]))
+}
+
+// wait waits until the deadline of the other direction is no longer in the past.
+func (c *deadlineCoordinator) wait(ctx context.Context, dir int) error {
+	for {
+		c.mu.Lock()
+		other, changed := c.deadlines[1-dir], c.changed
+		c.mu.Unlock()
+
+		if other.IsZero() || time.Now().Before(other) {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return canceled(ctx, "", 0, nil)
+		}
+	}
+}
+
+// A deadline sets the read or write deadline of an object on behalf of withCancelViaDeadline. It tracks the deadline
+// configured by the user so that each operation can apply the earlier of it and the context's deadline, and restore
+// it afterwards.
+//
+// Cancellation is handled by a watcher goroutine which is started on first use and shared by subsequent operations,
+// so the common case of a single operation at a time neither allocates nor starts a goroutine. The watcher exits
+// after it has been idle for watcherIdleTimeout.
+//
+// If the object turns out not to support deadlines, because setting one returns os.ErrNoDeadline, operations are
+// cancelled by the fallback instead, or fail if there is none.
+type deadline struct {
+	set func(time.Time) error
+
+	mu         sync.Mutex // guards following
+	user       time.Time  // the deadline configured by the user
+	op         time.Time  // the context deadline of the operation in progress
+	active     bool       // whether an operation is in progress
+	cancelled  bool       // whether the operation in progress has been cancelled
+	watching   bool       // whether the watcher is in use by an operation
+	watcher    bool       // whether the watcher goroutine is running
+	noDeadline bool       // whether the object returned os.ErrNoDeadline
+
+	// fallback cancels operations instead if the object returned os.ErrNoDeadline: StrategyCheck, StrategyClose
+	// via closer, or StrategyNone to fail. It's called directly rather than through a func value, so that the
+	// operations passed to withCancelViaDeadline don't escape.
+	fallback Strategy
+	closer   *closeOnCancel
+
+	watchCh chan (<-chan struct{}) // sends the Done channel of an operation to the watcher
+	stopCh  chan struct{}          // tells the watcher the operation has completed
+
+	coordinator *deadlineCoordinator // set if the deadline is shared with the other direction
+	dir         int                  // the direction of this deadline in the coordinator
+}
+
+// watcherIdleTimeout is how long the watcher goroutine waits for another operation before exiting.
+const watcherIdleTimeout = 10 * time.Second
+
+func newDeadline(set func(time.Time) error) *deadline {
+	return &deadline{
+		set:     set,
+		watchCh: make(chan (<-chan struct{})),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// setFallback sets the strategy used to cancel operations if the object doesn't support deadlines. closer is only
+// used by StrategyClose.
+func (d *deadline) setFallback(strategy Strategy, closer *closeOnCancel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fallback, d.closer = strategy, closer
+}
+
+// strategy reports StrategyDeadline, or the fallback's strategy once the object has turned out not to support
+// deadlines.
+func (d *deadline) strategy() Strategy {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.noDeadline && d.fallback != StrategyNone {
+		return d.fallback
+	}
+	return StrategyDeadline
+}
+
+// SetDeadline sets the user's deadline. If an operation is in progress, the earlier of the deadline and the
+// operation's deadline is applied immediately.
+func (d *deadline) SetDeadline(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.user = t
+	switch {
+	case d.cancelled:
+		// the operation will restore the user's deadline when it completes
+		return nil
+	case d.active:
+		return d.apply(earliest(d.user, d.op))
+	case d.coordinator != nil:
+		// the deadline is applied when the next operation begins
+		return nil
+	default:
+		return d.apply(d.user)
+	}
+}
+
+// begin starts an operation with the given context deadline, which may be zero. If the deadline can't be applied
+// the operation isn't started.
+func (d *deadline) begin(op time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.op, d.active, d.cancelled = op, true, false
+	if err := d.apply(earliest(d.user, d.op)); err != nil {
+		d.op, d.active = time.Time{}, false
+		return err
+	}
+	return nil
+}
+
+// cancel interrupts the operation in progress by setting a deadline in the past.
+func (d *deadline) cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.cancelled = true
+	_ = d.apply(time.Unix(1, 0))
+}
+
+// end completes an operation and restores the user's deadline.
+func (d *deadline) end() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.op, d.active, d.cancelled = time.Time{}, false, false
+	if d.coordinator != nil {
+		// an idle direction mustn't affect the other
+		return d.apply(time.Time{})
+	}
+	return d.apply(d.user)
+}
+
+// run runs an operation. If the deadline is shared with the other direction and the operation was interrupted by a
+// cancellation in that direction, a resumable operation is retried. Other operations return the interruption.
+func (d *deadline) run(ctx context.Context, resumable bool, operation func() error) error {
+	for {
+		err := operation()
+		if !resumable || d.coordinator == nil || !d.interrupted(err) {
+			return err
+		}
+		if d.coordinator.wait(ctx, d.dir) != nil {
+			return err
+		}
+	}
+}
+
+// interrupted returns true if err is a timeout which was caused by neither this direction's context nor the
+// user's deadline.
+func (d *deadline) interrupted(err error) bool {
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancelled {
+		return false
+	}
+	t := earliest(d.user, d.op)
+	return t.IsZero() || time.Now().Before(t)
+}
+
+// apply sets the deadline on the object. It's set every time rather than skipped when unchanged, since the caller
+// may have set a deadline on the object directly in the meantime.
+func (d *deadline) apply(t time.Time) error {
+	if d.noDeadline {
+		return os.ErrNoDeadline
+	}
+	err := d.set(t)
+	if errors.Is(err, os.ErrNoDeadline) {
+		d.noDeadline = true
+	}
+	return err
+}
+
+// earliest returns the earlier of two deadlines, where the zero time means no deadline.
+func earliest(a, b time.Time) time.Time {
+	switch {
+	case a.IsZero():
+		return b
+	case b.IsZero(), a.Before(b):
+		return a
+	default:
+		return b
+	}
+}
+
+// readDeadlineOf returns the deadline used to cancel reads on r, if r was returned by WrapIO and its reads are
+// cancelled via the deadline. Other objects have no deadline to share: one created here would overwrite any
+// deadline the caller has set on them.
+func readDeadlineOf(r interface{}) (*deadline, bool) {
+	w, ok := r.(interface{ readDeadline() (*deadline, bool) })
 	if !ok {
 		return nil, false
 	}
-
-	err := wsrd.SetReadDeadline(time.Time{})
-	return wsrd, !errors.Is(err, os.ErrNoDeadline)
+	if read, _ := StrategyOf(r); read != StrategyDeadline {
+		return nil, false
+	}
+	return w.readDeadline()
 }
 
-func supportsSetWriteDeadline(obj interface{}) (withSetWriteDeadline, bool) {
-	wswd, ok := obj.(withSetWriteDeadline)
+// writeDeadlineOf returns the deadline used to cancel writes on w, if w was returned by WrapIO and its writes are
+// cancelled via the deadline. See readDeadlineOf.
+func writeDeadlineOf(w interface{}) (*deadline, bool) {
+	wr, ok := w.(interface{ writeDeadline() (*deadline, bool) })
 	if !ok {
 		return nil, false
 	}
+	if _, write := StrategyOf(w); write != StrategyDeadline {
+		return nil, false
+	}
+	return wr.writeDeadline()
+}
+
+// acquireWatcher reserves the watcher for an operation, starting it if necessary. It returns false if the watcher is
+// already in use by a concurrent operation.
+func (d *deadline) acquireWatcher() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.watching {
+		return false
+	}
+	d.watching = true
+	if !d.watcher {
+		d.watcher = true
+		go d.watch()
+	}
+	return true
+}
+
+func (d *deadline) releaseWatcher() {
+	d.mu.Lock()
+	d.watching = false
+	d.mu.Unlock()
+}
 
-	err := wswd.SetWriteDeadline(time.Time{})
-	return wswd, !errors.Is(err, os.ErrNoDeadline)
+// watch runs the watcher goroutine. For each operation it receives the operation's Done channel and waits for
+// either the channel to fire, in which case the operation is cancelled, or for the operation to complete.
+func (d *deadline) watch() {
+	idle := time.NewTimer(watcherIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case done := <-d.watchCh:
+			select {
+			case <-done:
+				d.cancel()
+				<-d.stopCh
+			case <-d.stopCh:
+			}
+		case <-idle.C:
+			d.mu.Lock()
+			if !d.watching {
+				d.watcher = false
+				d.mu.Unlock()
+				return
+			}
+			// an operation has acquired the watcher and is about to send its Done channel
+			d.mu.Unlock()
+		}
+
+		if !idle.Stop() {
+			select {
+			case <-idle.C:
+			default:
+			}
+		}
+		idle.Reset(watcherIdleTimeout)
+	}
 }
 
+// withCancelViaDeadline runs a single-shot operation, such as one Read or Write, and cancels it by setting a deadline
+// in the past when the context is cancelled. The operation must be resumable: if it's interrupted by the other
+// direction of an object which only implements SetDeadline, it's called again.
 func withCancelViaDeadline(
 	ctx context.Context,
-	setDeadline func(time.Time) error,
-	operation func() (n int, err error),
-) (n int, err error) {
+	d *deadline,
+	operation func() error,
+) error {
+	return cancelViaDeadline(ctx, d, true, operation)
+}
+
+// withCancelViaDeadlineOnce is like withCancelViaDeadline, but for operations which can't be resumed, such as ReadFrom
+// and WriteTo, which may have consumed data they didn't get to write. If the operation is interrupted by the other
+// direction, the interruption is returned instead of retrying.
+func withCancelViaDeadlineOnce(
+	ctx context.Context,
+	d *deadline,
+	operation func() error,
+) error {
+	return cancelViaDeadline(ctx, d, false, operation)
+}
+
+func cancelViaDeadline(
+	ctx context.Context,
+	d *deadline,
+	resumable bool,
+	operation func() error,
+) (err error) {
 	// fail early
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return canceled(ctx, "", 0, nil)
 	default:
 	}
 
-	var cancelOnce sync.Once
-
-	// set the deadline from the context's deadline
+	// merge the context's deadline with the user's deadline
+	var opDeadline time.Time
 	if deadline, ok := ctx.Deadline(); ok {
-		err = setDeadline(deadline)
-		if err != nil {
-			return 0, err
-		}
-	} else {
-		// clear any existing deadline
-		err = setDeadline(time.Time{})
-		if err != nil {
-			return 0, err
+		opDeadline = deadline
+	}
+	err = d.begin(opDeadline)
+	if err != nil {
+		switch fallback, closer := d.fallbackFor(err); fallback {
+		case StrategyCheck:
+			return withCancelViaCheck(ctx, operation)
+		case StrategyClose:
+			return closer.withCancelViaClose(ctx, operation)
+		default:
+			return err
 		}
 	}
 
-	if done := ctx.Done(); done != nil {
-		doneCtx, doneCancel := context.WithCancel(ctx)
-		defer doneCancel()
+	switch done := ctx.Done(); {
+	case done == nil:
+		// the context can never be cancelled
+		err = d.run(ctx, resumable, operation)
+	case d.acquireWatcher():
+		d.watchCh <- done
+		err = d.run(ctx, resumable, operation)
+		// Once the watcher has received this it will not call `cancel` again until the next operation.
+		d.stopCh <- struct{}{}
+		d.releaseWatcher()
+	default:
+		err = withCancelViaGoroutine(ctx, d, resumable, operation)
+	}
+
+	// restore the user's deadline
+	_ = d.end()
+
+	// The deadline set on the object may fire slightly before the context's own timer does.
+	deadlinePassed := false
+	if deadline, ok := ctx.Deadline(); ok && errors.Is(err, os.ErrDeadlineExceeded) {
+		deadlinePassed = !time.Now().Before(deadline)
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		// already reported by a nested call
+		return err
+	case deadlinePassed || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &CanceledError{Context: context.DeadlineExceeded, Cause: causeOf(ctx), Err: err}
+	case errors.Is(ctx.Err(), context.Canceled):
+		return canceled(ctx, "", 0, err)
+	default:
+		return err
+	}
+}
+
+// fallbackFor returns the fallback to use if begin failed with err because the object doesn't support deadlines,
+// or StrategyNone.
+func (d *deadline) fallbackFor(err error) (Strategy, *closeOnCancel) {
+	if !errors.Is(err, os.ErrNoDeadline) {
+		return StrategyNone, nil
+	}
 
-		go func() {
-			<-doneCtx.Done()
-			cancelOnce.Do(func() {
-				_ = setDeadline(time.Unix(1, 0))
-			})
-		}()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.fallback, d.closer
+}
+
+// withCancelViaCheck runs an operation after checking that the context isn't done. It's the fallback for objects
+// which don't support deadlines when the default strategy is used.
+func withCancelViaCheck(ctx context.Context, operation func() error) error {
+	select {
+	case <-ctx.Done():
+		return canceled(ctx, "", 0, nil)
+	default:
 	}
+	return operation()
+}
+
+// withCancelViaGoroutine runs an operation, starting a new goroutine to cancel it if the context is cancelled. It is
+// used for concurrent operations when the watcher is already in use.
+func withCancelViaGoroutine(
+	ctx context.Context,
+	d *deadline,
+	resumable bool,
+	operation func() error,
+) (err error) {
+	var cancelOnce sync.Once
+
+	doneCtx, doneCancel := context.WithCancel(ctx)
+	defer doneCancel()
+
+	go func() {
+		<-doneCtx.Done()
+		cancelOnce.Do(d.cancel)
+	}()
 
 	// run the operation
-	n, err = operation()
+	err = d.run(ctx, resumable, operation)
 
-	// Clear any pending cancellation. There are 5 cases:
+	// Clear any pending cancellation. There are 4 cases:
 	//
-	// 1. There is no Done() channel
-	//    => execute the no-op, there is no background cancel
-	// 2. There is a Done() channel, but it hasn't fired yet
+	// 1. The Done() channel hasn't fired yet
 	//    => execute the no-op, background cancel won't happen
-	// 3. There is a Done() channel, and it has fired, but `cancelOnce` hasn't been called
+	// 2. The Done() channel has fired, but `cancelOnce` hasn't been called
 	//    => execute the no-op, background cancel call will be skipped via sync.Once guarantee
-	// 4. There is a Done() channel, and it has fired, and `cancelOnce` is currently being executed
+	// 3. The Done() channel has fired, and `cancelOnce` is currently being executed
 	//    => wait for background cancel call to complete
-	// 5. There is a Done() channel, and it has fired, and `cancelOnce` has already executed
+	// 4. The Done() channel has fired, and `cancelOnce` has already executed
 	//    => skip call via sync.Once guarantee
 	//
-	// In all 5 cases we guarantee that the background goroutine is cleaned up and will not call `SetDeadline` after
+	// In all 4 cases we guarantee that the background goroutine is cleaned up and will not call `SetDeadline` after
 	// this function returns.
 	cancelOnce.Do(func() {})
 
-	switch {
-	case err == nil:
-		return n, nil
-	case errors.Is(ctx.Err(), context.DeadlineExceeded):
-		return n, fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
-	case errors.Is(ctx.Err(), context.Canceled):
-		return n, fmt.Errorf("%w: %v", context.Canceled, err)
-	default:
-		return n, err
-	}
+	return err
 }
@@ -0,0 +1,169 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+)
+
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (eofReader) ReadContext(context.Context, []byte) (int, error) {
+	return 0, io.EOF
+}
+
+type multiReader struct {
+	readers []Reader
+}
+
+func (mr *multiReader) Read(p []byte) (n int, err error) {
+	return mr.ReadContext(context.Background(), p)
+}
+
+func (mr *multiReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	for len(mr.readers) > 0 {
+		// Optimization to flatten nested multiReaders (Issue 13558).
+		if len(mr.readers) == 1 {
+			if r, ok := mr.readers[0].(*multiReader); ok {
+				mr.readers = r.readers
+				continue
+			}
+		}
+		n, err = mr.readers[0].ReadContext(ctx, p)
+		if err == io.EOF {
+			// Use eofReader instead of nil to avoid nil panic
+			// after performing flatten (Issue 18232).
+			mr.readers[0] = eofReader{} // permit earlier GC
+			mr.readers = mr.readers[1:]
+		}
+		if n > 0 || err != io.EOF {
+			if err == io.EOF && len(mr.readers) > 0 {
+				// Don't return EOF yet. More readers remain.
+				err = nil
+			}
+			return
+		}
+	}
+	return 0, io.EOF
+}
+
+func (mr *multiReader) WriteTo(w io.Writer) (sum int64, err error) {
+	cw, ok := w.(Writer)
+	if !ok {
+		cw = NewWriter(w)
+	}
+	return mr.WriteToContext(context.Background(), cw)
+}
+
+func (mr *multiReader) WriteToContext(ctx context.Context, w Writer) (sum int64, err error) {
+	return mr.writeToWithBuffer(ctx, w, make([]byte, 1024*32))
+}
+
+func (mr *multiReader) writeToWithBuffer(ctx context.Context, w Writer, buf []byte) (sum int64, err error) {
+	for i, r := range mr.readers {
+		var n int64
+		if subMr, ok := r.(*multiReader); ok { // reuse buffer with nested multiReaders
+			n, err = subMr.writeToWithBuffer(ctx, w, buf)
+		} else {
+			n, err = copyBuffer(ctx, w, r, buf)
+		}
+		sum += n
+		if err != nil {
+			mr.readers = mr.readers[i:] // permit resume / retry after error
+			return sum, err
+		}
+		mr.readers[i] = nil // permit early GC
+	}
+	mr.readers = nil
+	return sum, nil
+}
+
+// MultiReader returns a Reader that's the logical concatenation of
+// the provided input readers. They're read sequentially. Once all
+// inputs have returned EOF, ReadContext will return EOF. If any of
+// the readers return a non-nil, non-EOF error, ReadContext will
+// return that error.
+//
+// A cancelled read can be retried: the MultiReader resumes with the
+// reader that was cancelled.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Readers.
+func MultiReader(readers ...Reader) Reader {
+	r := make([]Reader, len(readers))
+	copy(r, readers)
+	return &multiReader{r}
+}
+
+type multiWriter struct {
+	writers []Writer
+}
+
+func (t *multiWriter) Write(p []byte) (n int, err error) {
+	return t.WriteContext(context.Background(), p)
+}
+
+func (t *multiWriter) WriteContext(ctx context.Context, p []byte) (n int, err error) {
+	for _, w := range t.writers {
+		n, err = w.WriteContext(ctx, p)
+		if err != nil {
+			return
+		}
+		if n != len(p) {
+			err = io.ErrShortWrite
+			return
+		}
+	}
+	return len(p), nil
+}
+
+func (t *multiWriter) WriteString(s string) (n int, err error) {
+	return t.WriteStringContext(context.Background(), s)
+}
+
+func (t *multiWriter) WriteStringContext(ctx context.Context, s string) (n int, err error) {
+	var p []byte // lazily initialized if/when needed
+	for _, w := range t.writers {
+		if sw, ok := w.(StringWriter); ok {
+			n, err = sw.WriteStringContext(ctx, s)
+		} else {
+			if p == nil {
+				p = []byte(s)
+			}
+			n, err = w.WriteContext(ctx, p)
+		}
+		if err != nil {
+			return
+		}
+		if n != len(s) {
+			err = io.ErrShortWrite
+			return
+		}
+	}
+	return len(s), nil
+}
+
+// MultiWriter creates a writer that duplicates its writes to all the
+// provided writers, similar to the Unix tee(1) command.
+//
+// Each write is written to each listed writer, one at a time, using
+// the same context. If a listed writer returns an error, that
+// overall write operation stops and returns the error; it does not
+// continue down the list.
+//
+// This is copied from the stdlib and modified to use contextaware
+// Writers.
+func MultiWriter(writers ...Writer) Writer {
+	allWriters := make([]Writer, 0, len(writers))
+	for _, w := range writers {
+		if mw, ok := w.(*multiWriter); ok {
+			allWriters = append(allWriters, mw.writers...)
+		} else {
+			allWriters = append(allWriters, w)
+		}
+	}
+	return &multiWriter{allWriters}
+}
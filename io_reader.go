@@ -17,27 +17,62 @@ func NewReader(r io.Reader) Reader {
 	return WrapIO(r).(Reader)
 }
 
-func wrapReader(r io.Reader) Reader {
-	if cr, ok := r.(Reader); ok {
-		return cr
+// NewChunkedReader creates a new contextaware.Reader from an existing io.Reader which limits each call to Read to at
+// most chunkSize bytes. Objects which can't be interrupted only observe cancellation between calls to Read, so
+// bounding the size of each call bounds how long a cancelled ReadContext can block. If chunkSize is <= 0 a default
+// of 32KiB is used.
+func NewChunkedReader(r io.Reader, chunkSize int) Reader {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
 	}
-	if obj, ok := supportsSetReadDeadline(r); ok {
-		return readerViaSetDeadline{r, obj.SetReadDeadline}
+	return readerViaChunks{r, chunkSize}
+}
+
+// NewReaderWithOptions creates a new contextaware.Reader from an existing io.Reader using the given options. Unlike
+// NewReader, only the io.Reader interface is wrapped.
+func NewReaderWithOptions(r io.Reader, opts ...Option) (Reader, error) {
+	return wrapReader(r, newOptions(opts...))
+}
+
+func wrapReader(r io.Reader, o *options) (Reader, error) {
+	if cr, ok := r.(Reader); ok {
+		return cr, nil
 	}
-	if obj, ok := supportsSetDeadline(r); ok {
-		return readerViaSetDeadline{r, obj.SetDeadline}
+	switch o.strategy {
+	case StrategyNone, StrategyDeadline:
+		if d, ok := o.readDeadline(r); ok {
+			return readerViaSetDeadline{r, d}, nil
+		}
+		if o.strategy == StrategyDeadline {
+			return nil, errUnsupportedStrategy(o.strategy, "read from", r)
+		}
+		return readerViaRead{r}, nil
+	case StrategyCheck:
+		return readerViaRead{r}, nil
+	case StrategyBackground:
+		return NewBackgroundReader(r), nil
+	case StrategyChunked:
+		return readerViaChunks{r, o.chunkSize}, nil
+	case StrategyClose:
+		if c, ok := o.closeOnCancel(r); ok {
+			return readerViaClose{r, c}, nil
+		}
 	}
-	return readerViaRead{r}
+	return nil, errUnsupportedStrategy(o.strategy, "read from", r)
 }
 
 type readerViaRead struct {
 	io.Reader
 }
 
+func (r readerViaRead) strategy() Strategy {
+	return StrategyCheck
+}
+
 func (r readerViaRead) ReadContext(ctx context.Context, p []byte) (n int, err error) {
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return 0, canceled(ctx, "read", 0, nil)
 	default:
 	}
 	return r.Read(p)
@@ -45,11 +80,59 @@ func (r readerViaRead) ReadContext(ctx context.Context, p []byte) (n int, err er
 
 type readerViaSetDeadline struct {
 	io.Reader
-	setDeadline func(time.Time) error
+	d *deadline
+}
+
+func (r readerViaSetDeadline) strategy() Strategy {
+	return r.d.strategy()
+}
+
+func (r readerViaSetDeadline) unwrap() interface{} {
+	return r.Reader
+}
+
+func (r readerViaSetDeadline) readDeadline() (*deadline, bool) {
+	return r.d, true
+}
+
+// SetReadDeadline sets the deadline for future and pending reads. The deadline is combined with the deadline of
+// each context passed to ReadContext.
+func (r readerViaSetDeadline) SetReadDeadline(t time.Time) error {
+	return r.d.SetDeadline(t)
+}
+
+// Read reads using the background context, so that the read is tracked along with any concurrent operations.
+func (r readerViaSetDeadline) Read(p []byte) (n int, err error) {
+	return r.ReadContext(context.Background(), p)
 }
 
 func (r readerViaSetDeadline) ReadContext(ctx context.Context, p []byte) (n int, err error) {
-	return withCancelViaDeadline(ctx, r.setDeadline, func() (int, error) {
-		return r.Read(p)
+	err = withCancelViaDeadline(ctx, r.d, func() (err error) {
+		n, err = r.Reader.Read(p)
+		return err
 	})
+	return n, annotate(err, "read", int64(n))
+}
+
+type readerViaChunks struct {
+	io.Reader
+	chunkSize int
+}
+
+func (r readerViaChunks) strategy() Strategy {
+	return StrategyChunked
+}
+
+// ReadContext reads at most a single chunk. Since a Reader may return fewer bytes than requested, there is no need to
+// block waiting for additional chunks.
+func (r readerViaChunks) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	select {
+	case <-ctx.Done():
+		return 0, canceled(ctx, "read", 0, nil)
+	default:
+	}
+	if len(p) > r.chunkSize {
+		p = p[:r.chunkSize]
+	}
+	return r.Read(p)
 }
@@ -16,22 +16,151 @@ func NewReaderAt(ra io.ReaderAt) ReaderAt {
 	return WrapIO(ra).(ReaderAt)
 }
 
-func wrapReaderAt(ra io.ReaderAt) ReaderAt {
+func wrapReaderAt(ra io.ReaderAt, o *options) (ReaderAt, error) {
 	if cra, ok := ra.(ReaderAt); ok {
-		return cra
+		return cra, nil
 	}
-	return readerAtViaReadAt{ra}
+	switch o.strategy {
+	case StrategyNone, StrategyDeadline:
+		if d, ok := o.readDeadline(ra); ok {
+			return readerAtViaSetDeadline{ra, d}, nil
+		}
+		if o.strategy == StrategyDeadline {
+			return nil, errUnsupportedStrategy(o.strategy, "read at", ra)
+		}
+		return readerAtViaReadAt{ra}, nil
+	case StrategyCheck:
+		return readerAtViaReadAt{ra}, nil
+	case StrategyBackground:
+		return readerAtViaBackground{ra}, nil
+	case StrategyChunked:
+		return readerAtViaChunks{ra, o.chunkSize}, nil
+	case StrategyClose:
+		if c, ok := o.closeOnCancel(ra); ok {
+			return readerAtViaClose{ra, c}, nil
+		}
+	}
+	return nil, errUnsupportedStrategy(o.strategy, "read at", ra)
 }
 
 type readerAtViaReadAt struct {
 	io.ReaderAt
 }
 
+func (ra readerAtViaReadAt) strategy() Strategy {
+	return StrategyCheck
+}
+
 func (ra readerAtViaReadAt) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return 0, canceled(ctx, "read", 0, nil)
 	default:
 	}
 	return ra.ReadAt(p, off)
 }
+
+type readerAtViaSetDeadline struct {
+	io.ReaderAt
+	d *deadline
+}
+
+func (ra readerAtViaSetDeadline) strategy() Strategy {
+	return ra.d.strategy()
+}
+
+// ReadAt reads using the background context, so that the read is tracked along with any concurrent operations.
+func (ra readerAtViaSetDeadline) ReadAt(p []byte, off int64) (n int, err error) {
+	return ra.ReadAtContext(context.Background(), p, off)
+}
+
+func (ra readerAtViaSetDeadline) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	err = withCancelViaDeadline(ctx, ra.d, func() error {
+		nr, err := ra.ReaderAt.ReadAt(p[n:], off+int64(n))
+		n += nr
+		return err
+	})
+	return n, annotate(err, "read", int64(n))
+}
+
+type readerAtViaChunks struct {
+	io.ReaderAt
+	chunkSize int
+}
+
+func (ra readerAtViaChunks) strategy() Strategy {
+	return StrategyChunked
+}
+
+// ReadAtContext reads p in chunks, checking the context between them. Unlike ReadContext it can't return after a
+// single chunk, since ReadAt must fill p unless there is an error.
+func (ra readerAtViaChunks) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	for once := true; once || len(p) > 0; once = false {
+		select {
+		case <-ctx.Done():
+			return n, canceled(ctx, "read", n, nil)
+		default:
+		}
+		chunk := p
+		if len(chunk) > ra.chunkSize {
+			chunk = chunk[:ra.chunkSize]
+		}
+		nr, err := ra.ReadAt(chunk, off)
+		n += nr
+		if err != nil {
+			return n, err
+		}
+		p = p[nr:]
+		off += int64(nr)
+	}
+	return n, nil
+}
+
+type readerAtViaClose struct {
+	io.ReaderAt
+	c *closeOnCancel
+}
+
+func (ra readerAtViaClose) strategy() Strategy {
+	return StrategyClose
+}
+
+func (ra readerAtViaClose) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	err = ra.c.withCancelViaClose(ctx, func() (err error) {
+		n, err = ra.ReadAt(p, off)
+		return err
+	})
+	return n, annotate(err, "read", int64(n))
+}
+
+// readerAtViaBackground runs each ReadAt on a background goroutine. Since reads at an offset don't depend on each
+// other, a cancelled read is simply abandoned: it completes into its own buffer, which is discarded.
+type readerAtViaBackground struct {
+	io.ReaderAt
+}
+
+func (ra readerAtViaBackground) strategy() Strategy {
+	return StrategyBackground
+}
+
+func (ra readerAtViaBackground) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	select {
+	case <-ctx.Done():
+		return 0, canceled(ctx, "read", 0, nil)
+	default:
+	}
+
+	buf := make([]byte, len(p))
+	results := make(chan backgroundReadResult, 1)
+	go func() {
+		n, err := ra.ReadAt(buf, off)
+		results <- backgroundReadResult{n, err}
+	}()
+
+	select {
+	case res := <-results:
+		return copy(p, buf[:res.n]), res.err
+	case <-ctx.Done():
+		return 0, canceled(ctx, "read", 0, nil)
+	}
+}
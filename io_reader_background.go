@@ -0,0 +1,120 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+// A BackgroundReader is a contextaware.Reader which performs reads on a background goroutine. This makes it possible
+// to cancel reads on objects which have no other means of interruption, like os.Stdin or the stdout of an exec.Cmd.
+//
+// When the context passed to ReadContext is cancelled, ReadContext returns immediately but the underlying Read
+// continues in the background. Any data it returns is handed to the next call to ReadContext, so no data is lost.
+//
+// The background goroutine only exits when the underlying Read returns. Close unblocks it by closing the underlying
+// reader, but only if it implements io.Closer. Otherwise a read which never completes leaks its goroutine, along
+// with the reader and the buffer it's reading into.
+type BackgroundReader struct {
+	r io.Reader
+
+	mu      Mutex // serializes ReadContext calls, guards following
+	reading bool
+	results chan backgroundReadResult
+	scratch []byte
+	buf     []byte // unread data from a completed background read
+	err     error  // unreported error from a completed background read
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (br *BackgroundReader) strategy() Strategy {
+	return StrategyBackground
+}
+
+type backgroundReadResult struct {
+	n   int
+	err error
+}
+
+// NewBackgroundReader creates a new BackgroundReader from an existing io.Reader. If r doesn't implement io.Closer, a
+// pending background read can't be interrupted and its goroutine runs until the read completes, even after Close.
+func NewBackgroundReader(r io.Reader) *BackgroundReader {
+	return &BackgroundReader{
+		r:       r,
+		results: make(chan backgroundReadResult, 1),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Read reads data using the background context.
+func (br *BackgroundReader) Read(p []byte) (n int, err error) {
+	return br.ReadContext(context.Background(), p)
+}
+
+// ReadContext reads data into p. If a previous call was cancelled, the data from that call's background read is
+// returned first.
+func (br *BackgroundReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	if err := br.mu.LockContext(ctx); err != nil {
+		return 0, canceled(ctx, "read", 0, nil)
+	}
+	defer br.mu.Unlock()
+
+	select {
+	case <-br.closed:
+		return 0, os.ErrClosed
+	default:
+	}
+
+	if len(br.buf) == 0 && br.err == nil {
+		if !br.reading {
+			if len(p) == 0 {
+				return 0, nil
+			}
+			if cap(br.scratch) < len(p) {
+				br.scratch = make([]byte, len(p))
+			}
+			br.reading = true
+			go br.read(br.scratch[:len(p)])
+		}
+
+		select {
+		case res := <-br.results:
+			br.reading = false
+			br.buf, br.err = br.scratch[:res.n], res.err
+		case <-br.closed:
+			return 0, os.ErrClosed
+		case <-ctx.Done():
+			return 0, canceled(ctx, "read", 0, nil)
+		}
+	}
+
+	n = copy(p, br.buf)
+	br.buf = br.buf[n:]
+	if len(br.buf) == 0 {
+		err, br.err = br.err, nil
+	}
+	return n, err
+}
+
+// read runs on the background goroutine. Since results is buffered and only one read is in flight at a time, the
+// send never blocks and the goroutine exits as soon as the underlying Read returns.
+func (br *BackgroundReader) read(p []byte) {
+	n, err := br.r.Read(p)
+	br.results <- backgroundReadResult{n, err}
+}
+
+// Close closes the BackgroundReader. Any blocked calls to ReadContext return immediately. If the underlying reader
+// implements io.Closer it is closed, which unblocks a pending background read so its goroutine can exit. If it
+// doesn't, the pending read is abandoned and its goroutine exits whenever the underlying Read returns.
+func (br *BackgroundReader) Close() (err error) {
+	br.closeOnce.Do(func() {
+		close(br.closed)
+		if c, ok := br.r.(io.Closer); ok {
+			err = c.Close()
+		}
+	})
+	return err
+}
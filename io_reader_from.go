@@ -0,0 +1,105 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+)
+
+// A ReaderFrom is an io.ReaderFrom that also supports cancellation via a context.Context.
+type ReaderFrom interface {
+	io.ReaderFrom
+	ReadFromContext(ctx context.Context, r Reader) (n int64, err error)
+}
+
+// NewReaderFrom creates a contextaware.ReaderFrom from an existing io.ReaderFrom.
+func NewReaderFrom(rf io.ReaderFrom) ReaderFrom {
+	return WrapIO(rf).(ReaderFrom)
+}
+
+func wrapReaderFrom(rf io.ReaderFrom, o *options) (ReaderFrom, error) {
+	if crf, ok := rf.(ReaderFrom); ok {
+		return crf, nil
+	}
+	switch strategy := o.writeStrategy(); strategy {
+	case StrategyChunked, StrategyClose:
+		// ReadFrom would bypass the strategy, so leave it to Copy to use WriteContext instead
+		return nil, nil
+	case StrategyNone, StrategyDeadline:
+		if d, ok := o.writeDeadline(rf); ok {
+			if d.coordinator != nil {
+				// a cancelled read would interrupt ReadFrom, which can't be resumed, so leave it to Copy to use
+				// WriteContext instead
+				return nil, nil
+			}
+			return readerFromViaSetDeadline{rf, d}, nil
+		}
+		if strategy == StrategyDeadline {
+			return nil, errUnsupportedStrategy(strategy, "read into", rf)
+		}
+		return readerFromViaReadFrom{rf}, nil
+	case StrategyCheck:
+		return readerFromViaReadFrom{rf}, nil
+	}
+	return nil, errUnsupportedStrategy(o.strategy, "read into", rf)
+}
+
+type readerFromViaReadFrom struct {
+	io.ReaderFrom
+}
+
+func (rf readerFromViaReadFrom) strategy() Strategy {
+	return StrategyCheck
+}
+
+func (rf readerFromViaReadFrom) ReadFromContext(ctx context.Context, r Reader) (n int64, err error) {
+	select {
+	case <-ctx.Done():
+		return 0, canceled(ctx, "readfrom", 0, nil)
+	default:
+	}
+	return rf.ReadFrom(contextReader{ctx, r})
+}
+
+type readerFromViaSetDeadline struct {
+	io.ReaderFrom
+	d *deadline
+}
+
+func (rf readerFromViaSetDeadline) strategy() Strategy {
+	return rf.d.strategy()
+}
+
+// ReadFrom reads using the background context, so that the operation is tracked along with any concurrent
+// operations.
+func (rf readerFromViaSetDeadline) ReadFrom(r io.Reader) (n int64, err error) {
+	err = withCancelViaDeadlineOnce(context.Background(), rf.d, func() error {
+		nr, err := rf.ReaderFrom.ReadFrom(r)
+		n += nr
+		return err
+	})
+	return n, annotate(err, "readfrom", n)
+}
+
+func (rf readerFromViaSetDeadline) ReadFromContext(ctx context.Context, r Reader) (n int64, err error) {
+	// If the source also supports deadlines, hand the underlying object to ReadFrom so that the stdlib can use
+	// splice or sendfile, and cancel both sides via their deadlines.
+	if src, ok := unwrap(r).(io.Reader); ok {
+		if srcDeadline, ok := readDeadlineOf(r); ok {
+			err = withCancelViaDeadlineOnce(ctx, rf.d, func() error {
+				return withCancelViaDeadlineOnce(ctx, srcDeadline, func() error {
+					nr, err := rf.ReaderFrom.ReadFrom(src)
+					n += nr
+					return err
+				})
+			})
+			return n, annotate(err, "readfrom", n)
+		}
+	}
+
+	err = withCancelViaDeadlineOnce(ctx, rf.d, func() error {
+		nr, err := rf.ReaderFrom.ReadFrom(contextReader{ctx, r})
+		n += nr
+		return err
+	})
+	return n, annotate(err, "readfrom", n)
+}
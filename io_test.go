@@ -3,8 +3,15 @@ package contextaware
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -94,3 +101,1107 @@ func TestDeadlineReadWrite(t *testing.T) {
 		assert.ErrorIs(t, err, context.Canceled)
 	})
 }
+
+func TestWriteAt(t *testing.T) {
+	newFile := func(t *testing.T) *os.File {
+		f, err := os.CreateTemp(t.TempDir(), "contextaware-*")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+		return f
+	}
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, clearTimeout := context.WithTimeout(context.Background(), -1)
+		defer clearTimeout()
+
+		n, err := NewWriterAt(newFile(t)).WriteAtContext(ctx, []byte{1, 2, 3, 4}, 2)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		n, err := NewWriterAt(newFile(t)).WriteAtContext(ctx, []byte{1, 2, 3, 4}, 2)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+
+		f := newFile(t)
+		n, err := NewWriterAt(f).WriteAtContext(ctx, []byte{1, 2, 3, 4}, 2)
+		assert.Equal(t, 4, n)
+		assert.NoError(t, err)
+		p := make([]byte, 6)
+		n, err = f.ReadAt(p, 0)
+		assert.Equal(t, 6, n)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0, 0, 1, 2, 3, 4}, p)
+	})
+}
+
+// connWriterAt adapts a net.Conn to an io.WriterAt by ignoring the offset.
+type connWriterAt struct {
+	c net.Conn
+}
+
+func (wa connWriterAt) WriteAt(p []byte, off int64) (n int, err error) {
+	return wa.c.Write(p)
+}
+
+func (wa connWriterAt) SetWriteDeadline(t time.Time) error {
+	return wa.c.SetWriteDeadline(t)
+}
+
+func TestDeadlineWriteAt(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	cwa := NewWriterAt(connWriterAt{c1})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer clearTimeout()
+
+		n, err := cwa.WriteAtContext(ctx, []byte{1, 2, 3, 4}, 0)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, func() {
+			cancel()
+		})
+
+		n, err := cwa.WriteAtContext(ctx, []byte{1, 2, 3, 4}, 0)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+
+		go func() {
+			p := make([]byte, 4)
+			_, _ = io.ReadFull(c2, p)
+		}()
+
+		n, err := cwa.WriteAtContext(ctx, []byte{1, 2, 3, 4}, 0)
+		assert.Equal(t, 4, n)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCopy(t *testing.T) {
+	t.Run("buffer", func(t *testing.T) {
+		ctx := context.Background()
+
+		src := bytes.NewBufferString("EXAMPLE")
+		var dst bytes.Buffer
+		n, err := Copy(ctx, NewWriter(&dst), NewReader(src))
+		assert.Equal(t, int64(7), n)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXAMPLE", dst.String())
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, func() {
+			cancel()
+		})
+
+		pr, pw := Pipe()
+		defer pw.Close()
+
+		var dst bytes.Buffer
+		n, err := Copy(ctx, NewWriter(&dst), pr)
+		assert.Equal(t, int64(0), n)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestCopyTCP(t *testing.T) {
+	dial := func(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+		li, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer li.Close()
+
+		c1, err := net.Dial("tcp", li.Addr().String())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c1.Close() })
+
+		c2, err := li.Accept()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c2.Close() })
+
+		return c1.(*net.TCPConn), c2.(*net.TCPConn)
+	}
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, func() {
+			cancel()
+		})
+
+		_, src := dial(t)
+		dst, _ := dial(t)
+
+		n, err := Copy(ctx, NewWriter(dst), NewReader(src))
+		assert.Equal(t, int64(0), n)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+
+		in, src := dial(t)
+		dst, out := dial(t)
+
+		_, err := in.Write([]byte("EXAMPLE"))
+		require.NoError(t, err)
+		require.NoError(t, in.CloseWrite())
+
+		n, err := Copy(ctx, NewWriter(dst), NewReader(src))
+		assert.Equal(t, int64(7), n)
+		assert.NoError(t, err)
+		require.NoError(t, dst.CloseWrite())
+
+		p, err := io.ReadAll(out)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXAMPLE", string(p))
+	})
+	t.Run("background", func(t *testing.T) {
+		in, src := dial(t)
+		dst, out := dial(t)
+
+		wrapped, err := WrapIOWithOptions(src, WithStrategy(StrategyBackground))
+		require.NoError(t, err)
+		r := wrapped.(Reader)
+
+		// leave a read in flight in the background reader
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer clearTimeout()
+		_, err = r.ReadContext(ctx, make([]byte, 16))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		go func() {
+			_, _ = in.Write([]byte("hello"))
+			time.Sleep(time.Millisecond * 50)
+			_, _ = in.Write([]byte("world"))
+			_ = in.CloseWrite()
+		}()
+
+		n, err := Copy(context.Background(), NewWriter(dst), r)
+		assert.Equal(t, int64(10), n)
+		assert.NoError(t, err)
+		require.NoError(t, dst.CloseWrite())
+
+		p, err := io.ReadAll(out)
+		assert.NoError(t, err)
+		assert.Equal(t, "helloworld", string(p))
+	})
+	t.Run("peer deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+		defer cancel()
+
+		dst, _ := dial(t)
+		src, other := ConnPipe()
+		defer func() { _ = other.Close() }()
+		require.NoError(t, src.SetReadDeadline(time.Now().Add(time.Millisecond*200)))
+
+		start := time.Now()
+		_, err := Copy(ctx, WrapIO(dst).(Writer), src)
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestIODifferential(t *testing.T) {
+	errExample := errors.New("example error")
+	sources := map[string]func() io.Reader{
+		"empty":    func() io.Reader { return strings.NewReader("") },
+		"short":    func() io.Reader { return strings.NewReader("abc") },
+		"long":     func() io.Reader { return strings.NewReader(strings.Repeat("0123456789", 100)) },
+		"one-byte": func() io.Reader { return iotest.OneByteReader(strings.NewReader("hello world")) },
+		"half":     func() io.Reader { return iotest.HalfReader(strings.NewReader("hello world")) },
+		"data-err": func() io.Reader { return iotest.DataErrReader(strings.NewReader("hello world")) },
+		"timeout":  func() io.Reader { return iotest.TimeoutReader(strings.NewReader("hello world")) },
+		"error":    func() io.Reader { return iotest.ErrReader(errExample) },
+		"partial":  func() io.Reader { return io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errExample)) },
+	}
+
+	ctx := context.Background()
+	for name, src := range sources {
+		src := src
+		t.Run(name, func(t *testing.T) {
+			for _, size := range []int{0, 1, 5, 11, 20, 2000} {
+				expectedBuf, actualBuf := make([]byte, size), make([]byte, size)
+				expectedN, expectedErr := io.ReadFull(src(), expectedBuf)
+				actualN, actualErr := ReadFull(ctx, NewReader(src()), actualBuf)
+				assert.Equal(t, expectedN, actualN, "ReadFull(%d)", size)
+				assert.Equal(t, expectedErr, actualErr, "ReadFull(%d)", size)
+				assert.Equal(t, expectedBuf, actualBuf, "ReadFull(%d)", size)
+
+				for _, min := range []int{0, 1, size, size + 1} {
+					expectedBuf, actualBuf := make([]byte, size), make([]byte, size)
+					expectedN, expectedErr := io.ReadAtLeast(src(), expectedBuf, min)
+					actualN, actualErr := ReadAtLeast(ctx, NewReader(src()), actualBuf, min)
+					assert.Equal(t, expectedN, actualN, "ReadAtLeast(%d, %d)", size, min)
+					assert.Equal(t, expectedErr, actualErr, "ReadAtLeast(%d, %d)", size, min)
+					assert.Equal(t, expectedBuf, actualBuf, "ReadAtLeast(%d, %d)", size, min)
+				}
+
+				var expectedDst, actualDst bytes.Buffer
+				expectedN64, expectedErr := io.CopyN(&expectedDst, src(), int64(size))
+				actualN64, actualErr := CopyN(ctx, NewWriter(&actualDst), NewReader(src()), int64(size))
+				assert.Equal(t, expectedN64, actualN64, "CopyN(%d)", size)
+				assert.Equal(t, expectedErr, actualErr, "CopyN(%d)", size)
+				assert.Equal(t, expectedDst.String(), actualDst.String(), "CopyN(%d)", size)
+
+				if size > 0 {
+					expectedDst.Reset()
+					actualDst.Reset()
+					expectedN64, expectedErr := io.CopyBuffer(&expectedDst, src(), make([]byte, size))
+					actualN64, actualErr := CopyBuffer(ctx, NewWriter(&actualDst), NewReader(src()), make([]byte, size))
+					assert.Equal(t, expectedN64, actualN64, "CopyBuffer(%d)", size)
+					assert.Equal(t, expectedErr, actualErr, "CopyBuffer(%d)", size)
+					assert.Equal(t, expectedDst.String(), actualDst.String(), "CopyBuffer(%d)", size)
+				}
+			}
+
+			expected, expectedErr := io.ReadAll(src())
+			actual, actualErr := ReadAll(ctx, NewReader(src()))
+			assert.Equal(t, expected, actual, "ReadAll")
+			assert.Equal(t, expectedErr, actualErr, "ReadAll")
+
+			for _, n := range []int64{0, 5, 2000} {
+				expected, expectedErr := io.ReadAll(io.LimitReader(src(), n))
+				actual, actualErr := ReadAll(ctx, LimitReader(NewReader(src()), n))
+				assert.Equal(t, expected, actual, "LimitReader(%d)", n)
+				assert.Equal(t, expectedErr, actualErr, "LimitReader(%d)", n)
+			}
+
+			var expectedTee, actualTee bytes.Buffer
+			expected, expectedErr = io.ReadAll(io.TeeReader(src(), &expectedTee))
+			actual, actualErr = ReadAll(ctx, TeeReader(NewReader(src()), NewWriter(&actualTee)))
+			assert.Equal(t, expected, actual, "TeeReader")
+			assert.Equal(t, expectedErr, actualErr, "TeeReader")
+			assert.Equal(t, expectedTee.String(), actualTee.String(), "TeeReader")
+
+			expected, expectedErr = io.ReadAll(io.MultiReader(src(), strings.NewReader("xyz"), src()))
+			actual, actualErr = ReadAll(ctx, MultiReader(NewReader(src()), NewReader(strings.NewReader("xyz")), NewReader(src())))
+			assert.Equal(t, expected, actual, "MultiReader")
+			assert.Equal(t, expectedErr, actualErr, "MultiReader")
+
+			var expectedDst, actualDst bytes.Buffer
+			expectedN64, expectedErr := io.Copy(&expectedDst, io.MultiReader(src(), src()))
+			actualN64, actualErr := Copy(ctx, NewWriter(&actualDst), MultiReader(NewReader(src()), NewReader(src())))
+			assert.Equal(t, expectedN64, actualN64, "Copy(MultiReader)")
+			assert.Equal(t, expectedErr, actualErr, "Copy(MultiReader)")
+			assert.Equal(t, expectedDst.String(), actualDst.String(), "Copy(MultiReader)")
+		})
+	}
+
+	t.Run("section-reader", func(t *testing.T) {
+		src := strings.NewReader("0123456789")
+		for _, section := range [][2]int64{{0, 10}, {2, 5}, {8, 5}, {12, 1}} {
+			expected := io.NewSectionReader(src, section[0], section[1])
+			actual := NewSectionReader(NewReaderAt(src), section[0], section[1])
+			assert.Equal(t, expected.Size(), actual.Size(), "Size(%v)", section)
+
+			for _, off := range []int64{-1, 0, 1, 4, 5, 10} {
+				expectedBuf, actualBuf := make([]byte, 4), make([]byte, 4)
+				expectedN, expectedErr := expected.ReadAt(expectedBuf, off)
+				actualN, actualErr := actual.ReadAtContext(ctx, actualBuf, off)
+				assert.Equal(t, expectedN, actualN, "ReadAt(%v, %d)", section, off)
+				assert.Equal(t, expectedErr, actualErr, "ReadAt(%v, %d)", section, off)
+				assert.Equal(t, expectedBuf, actualBuf, "ReadAt(%v, %d)", section, off)
+			}
+
+			for _, whence := range []int{io.SeekStart, io.SeekCurrent, io.SeekEnd, 3} {
+				expectedOff, expectedErr := expected.Seek(1, whence)
+				actualOff, actualErr := actual.Seek(1, whence)
+				assert.Equal(t, expectedOff, actualOff, "Seek(%v, %d)", section, whence)
+				assert.Equal(t, expectedErr, actualErr, "Seek(%v, %d)", section, whence)
+
+				expectedBuf, expectedErr := io.ReadAll(expected)
+				actualBuf, actualErr := ReadAll(ctx, actual)
+				assert.Equal(t, expectedBuf, actualBuf, "Read(%v, %d)", section, whence)
+				assert.Equal(t, expectedErr, actualErr, "Read(%v, %d)", section, whence)
+			}
+		}
+	})
+	t.Run("multi-writer", func(t *testing.T) {
+		var expected1, expected2, actual1, actual2 bytes.Buffer
+		expected := io.MultiWriter(&expected1, io.MultiWriter(&expected2))
+		actual := MultiWriter(NewWriter(&actual1), MultiWriter(NewWriter(&actual2)))
+
+		expectedN, expectedErr := io.WriteString(expected, "hello ")
+		actualN, actualErr := WriteString(ctx, actual, "hello ")
+		assert.Equal(t, expectedN, actualN)
+		assert.Equal(t, expectedErr, actualErr)
+		expectedN, expectedErr = expected.Write([]byte("world"))
+		actualN, actualErr = actual.WriteContext(ctx, []byte("world"))
+		assert.Equal(t, expectedN, actualN)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, expected1.String(), actual1.String())
+		assert.Equal(t, expected2.String(), actual2.String())
+	})
+
+	t.Run("write-string", func(t *testing.T) {
+		var expected, actual bytes.Buffer
+		expectedN, expectedErr := io.WriteString(&expected, "EXAMPLE")
+		actualN, actualErr := WriteString(ctx, NewWriter(&actual), "EXAMPLE")
+		assert.Equal(t, expectedN, actualN)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, expected.String(), actual.String())
+	})
+	t.Run("short-buffer", func(t *testing.T) {
+		assert.PanicsWithValue(t, "empty buffer in CopyBuffer", func() {
+			_, _ = CopyBuffer(ctx, NewWriter(io.Discard), NewReader(strings.NewReader("")), []byte{})
+		})
+	})
+}
+
+func TestIOCancel(t *testing.T) {
+	newSource := func(t *testing.T) (context.Context, *PipeReader) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		pr, pw := Pipe()
+		t.Cleanup(func() { _ = pw.Close() })
+		go func() {
+			_, _ = pw.WriteContext(ctx, []byte("abc"))
+			time.AfterFunc(time.Millisecond*100, cancel)
+		}()
+		return ctx, pr
+	}
+
+	t.Run("read-full", func(t *testing.T) {
+		ctx, pr := newSource(t)
+		p := make([]byte, 8)
+		n, err := ReadFull(ctx, pr, p)
+		assert.Equal(t, 3, n)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "abc", string(p[:n]))
+	})
+	t.Run("read-all", func(t *testing.T) {
+		ctx, pr := newSource(t)
+		p, err := ReadAll(ctx, pr)
+		assert.Equal(t, "abc", string(p))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("copy-n", func(t *testing.T) {
+		ctx, pr := newSource(t)
+		var buf bytes.Buffer
+		n, err := CopyN(ctx, NewWriter(&buf), pr, 8)
+		assert.Equal(t, int64(3), n)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "abc", buf.String())
+	})
+	t.Run("multi-reader", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		pr, pw := Pipe()
+		r := MultiReader(NewReader(strings.NewReader("xyz")), pr, NewReader(strings.NewReader("def")))
+		p, err := ReadAll(ctx, r)
+		assert.Equal(t, "xyz", string(p))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// reading resumes with the cancelled reader
+		_ = pw.Close()
+		p, err = ReadAll(context.Background(), r)
+		assert.Equal(t, "def", string(p))
+		assert.NoError(t, err)
+	})
+	t.Run("tee-reader", func(t *testing.T) {
+		ctx, pr := newSource(t)
+		var buf bytes.Buffer
+		p, err := ReadAll(ctx, TeeReader(pr, NewWriter(&buf)))
+		assert.Equal(t, "abc", string(p))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "abc", buf.String())
+	})
+	t.Run("multi-writer", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		pr, pw := Pipe()
+		defer pr.Close()
+		var buf bytes.Buffer
+		n, err := MultiWriter(NewWriter(&buf), pw).WriteContext(ctx, []byte("abc"))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "abc", buf.String())
+	})
+	t.Run("section-reader", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		r := NewSectionReader(NewReaderAt(strings.NewReader("0123456789")), 2, 5)
+		_, err := r.ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = r.ReadAtContext(ctx, make([]byte, 4), 0)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the offset is unchanged
+		p, err := ReadAll(context.Background(), r)
+		assert.Equal(t, "23456", string(p))
+		assert.NoError(t, err)
+	})
+}
+
+func TestBackgroundReader(t *testing.T) {
+	t.Run("cancel", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		br := NewBackgroundReader(pr)
+		defer br.Close()
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer clearTimeout()
+
+		p := make([]byte, 4)
+		n, err := br.ReadContext(ctx, p)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("ordering", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		br := NewBackgroundReader(pr)
+		defer br.Close()
+
+		var received []byte
+		for _, chunk := range []string{"abcd", "efgh", "ijkl"} {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			n, err := br.ReadContext(ctx, make([]byte, 4))
+			assert.Equal(t, 0, n)
+			assert.ErrorIs(t, err, context.Canceled)
+
+			go func(chunk string) {
+				_, _ = pw.Write([]byte(chunk))
+			}(chunk)
+
+			// read in two halves to make sure leftover data is preserved
+			for i := 0; i < 2; i++ {
+				p := make([]byte, 2)
+				n, err := br.ReadContext(context.Background(), p)
+				assert.NoError(t, err)
+				received = append(received, p[:n]...)
+			}
+		}
+		assert.Equal(t, "abcdefghijkl", string(received))
+	})
+	t.Run("eof", func(t *testing.T) {
+		br := NewBackgroundReader(strings.NewReader("EXAMPLE"))
+		defer br.Close()
+
+		p, err := ReadAll(context.Background(), br)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXAMPLE", string(p))
+	})
+	t.Run("close", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		r := &returnTrackingReader{r: pr, returned: make(chan struct{})}
+		br := NewBackgroundReader(r)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, cancel)
+		_, err := br.ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		assert.NoError(t, br.Close())
+		_, err = br.ReadContext(context.Background(), make([]byte, 4))
+		assert.ErrorIs(t, err, os.ErrClosed)
+
+		select {
+		case <-r.returned:
+		case <-time.After(time.Second):
+			t.Fatal("background read was not unblocked by Close")
+		}
+	})
+}
+
+// returnTrackingReader is an io.ReadCloser which signals when its first Read returns.
+type returnTrackingReader struct {
+	r        io.ReadCloser
+	once     sync.Once
+	returned chan struct{}
+}
+
+func (r *returnTrackingReader) Read(p []byte) (n int, err error) {
+	defer r.once.Do(func() { close(r.returned) })
+	return r.r.Read(p)
+}
+
+func (r *returnTrackingReader) Close() error {
+	return r.r.Close()
+}
+
+// slowWriter is an io.Writer which sleeps before every write.
+type slowWriter struct {
+	delay time.Duration
+	buf   bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (n int, err error) {
+	time.Sleep(w.delay)
+	return w.buf.Write(p)
+}
+
+func TestChunked(t *testing.T) {
+	t.Run("write", func(t *testing.T) {
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer clearTimeout()
+
+		sw := &slowWriter{delay: time.Millisecond * 10}
+		p := make([]byte, 1024*1024)
+		n, err := NewChunkedWriter(sw, 1024).WriteContext(ctx, p)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Greater(t, n, 0)
+		assert.Less(t, n, len(p))
+		assert.Equal(t, n%1024, 0)
+		assert.Equal(t, n, sw.buf.Len())
+	})
+	t.Run("write-success", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := NewChunkedWriter(&buf, 3).WriteContext(context.Background(), []byte("EXAMPLE"))
+		assert.Equal(t, 7, n)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXAMPLE", buf.String())
+	})
+	t.Run("read", func(t *testing.T) {
+		r := NewChunkedReader(strings.NewReader("EXAMPLE"), 3)
+		p := make([]byte, 7)
+		n, err := r.ReadContext(context.Background(), p)
+		assert.Equal(t, 3, n)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXA", string(p[:n]))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		n, err = r.ReadContext(ctx, p)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestCloseOnCancel(t *testing.T) {
+	t.Run("read", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		r, err := NewReaderWithOptions(pr, WithStrategy(StrategyClose))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, cancel)
+
+		n, err := r.ReadContext(ctx, make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		n, err = r.ReadContext(context.Background(), make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, ErrClosedByCancel)
+	})
+	t.Run("write", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+
+		w, err := NewWriterWithOptions(pw, WithStrategy(StrategyClose))
+		require.NoError(t, err)
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer clearTimeout()
+
+		n, err := w.WriteContext(ctx, []byte{1, 2, 3, 4})
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		n, err = w.WriteContext(context.Background(), []byte{1, 2, 3, 4})
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, ErrClosedByCancel)
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		// cancelling the read closes the object, which interrupts the write
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+
+		rw, err := WrapIOWithOptions(noDeadlineConn{c2}, WithStrategy(StrategyClose))
+		require.NoError(t, err)
+
+		written := make(chan error, 1)
+		go func() {
+			_, err := rw.(Writer).WriteContext(context.Background(), []byte{1, 2, 3, 4})
+			written <- err
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, cancel)
+
+		_, err = rw.(Reader).ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+		err = <-written
+		assert.ErrorIs(t, err, ErrClosedByCancel)
+		assert.NotErrorIs(t, err, io.ErrClosedPipe)
+	})
+	t.Run("success", func(t *testing.T) {
+		r, err := NewReaderWithOptions(io.NopCloser(strings.NewReader("EXAMPLE")), WithStrategy(StrategyClose))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		p, err := ReadAll(ctx, r)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXAMPLE", string(p))
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewReaderWithOptions(strings.NewReader("EXAMPLE"), WithStrategy(StrategyClose))
+		assert.ErrorIs(t, err, ErrUnsupportedStrategy)
+	})
+}
+
+func TestUserDeadline(t *testing.T) {
+	li, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer li.Close()
+
+	c1, err := net.Dial("tcp", li.Addr().String())
+	require.NoError(t, err)
+	defer c1.Close()
+
+	c2, err := li.Accept()
+	require.NoError(t, err)
+	defer c2.Close()
+
+	cr := NewReader(c1)
+	wsrd, ok := cr.(interface{ SetReadDeadline(time.Time) error })
+	require.True(t, ok)
+	defer func() { _ = wsrd.SetReadDeadline(time.Time{}) }()
+
+	t.Run("user", func(t *testing.T) {
+		require.NoError(t, wsrd.SetReadDeadline(time.Now().Add(time.Millisecond*100)))
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Second*5)
+		defer clearTimeout()
+
+		n, err := cr.ReadContext(ctx, make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("restored", func(t *testing.T) {
+		require.NoError(t, wsrd.SetReadDeadline(time.Now().Add(time.Millisecond*300)))
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer clearTimeout()
+
+		n, err := cr.ReadContext(ctx, make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// the user's deadline should still be in effect
+		ctx, clearTimeout = context.WithTimeout(context.Background(), time.Second*5)
+		defer clearTimeout()
+
+		n, err = cr.ReadContext(ctx, make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("cleared", func(t *testing.T) {
+		require.NoError(t, wsrd.SetReadDeadline(time.Now().Add(-time.Second)))
+		require.NoError(t, wsrd.SetReadDeadline(time.Time{}))
+
+		go func() {
+			_, _ = c2.Write([]byte{1, 2, 3, 4})
+		}()
+
+		n, err := cr.ReadContext(context.Background(), make([]byte, 4))
+		assert.Equal(t, 4, n)
+		assert.NoError(t, err)
+	})
+	t.Run("set directly", func(t *testing.T) {
+		// a deadline set on the original conn is replaced by the wrapper's when an operation begins
+		require.NoError(t, c1.SetReadDeadline(time.Now().Add(-time.Second)))
+
+		go func() {
+			_, _ = c2.Write([]byte{1, 2, 3, 4})
+		}()
+
+		n, err := cr.ReadContext(context.Background(), make([]byte, 4))
+		assert.Equal(t, 4, n)
+		assert.NoError(t, err)
+	})
+	t.Run("wrap", func(t *testing.T) {
+		// wrapping doesn't clear a deadline which was already set
+		require.NoError(t, c2.SetReadDeadline(time.Now().Add(time.Millisecond*100)))
+		defer func() { _ = c2.SetReadDeadline(time.Time{}) }()
+		_ = WrapIO(c2)
+
+		// unblock the read if the deadline was cleared
+		guard := time.AfterFunc(time.Second, func() { _ = c2.SetReadDeadline(time.Now()) })
+		defer guard.Stop()
+
+		start := time.Now()
+		n, err := c2.Read(make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second/2)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		// a regular file only turns out not to support deadlines once one is set
+		f, err := os.CreateTemp(t.TempDir(), "contextaware-*")
+		require.NoError(t, err)
+		defer f.Close()
+
+		w := NewWriter(f)
+		_, write := StrategyOf(w)
+		assert.Equal(t, StrategyDeadline, write)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		n, err := w.WriteContext(ctx, []byte{1, 2, 3, 4})
+		assert.Equal(t, 4, n)
+		assert.NoError(t, err)
+		_, write = StrategyOf(w)
+		assert.Equal(t, StrategyCheck, write)
+
+		// asking for deadlines explicitly checks for support up front
+		_, err = WrapIOWithOptions(f, WithStrategy(StrategyDeadline))
+		assert.ErrorIs(t, err, ErrUnsupportedStrategy)
+		_, err = NewWriterWithOptions(f, WithStrategy(StrategyDeadline))
+		assert.ErrorIs(t, err, ErrUnsupportedStrategy)
+	})
+}
+
+func TestCancelViaDeadline(t *testing.T) {
+	noop := func() error { return nil }
+
+	t.Run("allocs", func(t *testing.T) {
+		d := newDeadline(func(time.Time) error { return nil })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+			_ = withCancelViaDeadline(context.Background(), d, noop)
+		}))
+		assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+			_ = withCancelViaDeadline(ctx, d, noop)
+		}))
+
+		r, w := NewReader(deadlineFileLike{}), NewWriter(deadlineFileLike{})
+		p := make([]byte, 16)
+		for _, ctx := range []context.Context{context.Background(), ctx} {
+			assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+				_, _ = r.ReadContext(ctx, p)
+			}))
+			assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+				_, _ = w.WriteContext(ctx, p)
+			}))
+		}
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		cr := NewReader(c1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, cancel)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				n, err := cr.ReadContext(ctx, make([]byte, 4))
+				assert.Equal(t, 0, n)
+				assert.ErrorIs(t, err, context.Canceled)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func BenchmarkCancelViaDeadline(b *testing.B) {
+	noop := func() error { return nil }
+	d := newDeadline(func(time.Time) error { return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.Run("goroutine", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = d.begin(time.Time{})
+			_ = withCancelViaGoroutine(ctx, d, true, noop)
+			_ = d.end()
+		}
+	})
+	b.Run("watcher", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = withCancelViaDeadline(ctx, d, noop)
+		}
+	})
+	b.Run("background", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = withCancelViaDeadline(context.Background(), d, noop)
+		}
+	})
+	b.Run("copy unwrapped", func(b *testing.B) {
+		// the source isn't returned by WrapIO, so it is read via ReadContext without touching its deadline
+		data := make([]byte, 1024)
+		src := &deadlineReader{}
+		dst := WrapIO(deadlineFileLike{}).(Writer)
+		goroutines := runtime.NumGoroutine()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			src.Reset(data)
+			_, _ = Copy(ctx, dst, src)
+		}
+		b.StopTimer()
+
+		// only the watcher of dst should remain
+		for start := time.Now(); runtime.NumGoroutine()-goroutines > 1 && time.Since(start) < time.Second; {
+			time.Sleep(time.Millisecond)
+		}
+		if n := runtime.NumGoroutine() - goroutines; n > 1 {
+			b.Errorf("%d goroutines left running", n)
+		}
+	})
+}
+
+// deadlineReader is a contextaware Reader which supports read deadlines and isn't returned by WrapIO.
+type deadlineReader struct {
+	bytes.Reader
+}
+
+func (r *deadlineReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	return r.Read(p)
+}
+
+func (r *deadlineReader) SetReadDeadline(time.Time) error { return nil }
+
+// deadlineOnlyConn hides the SetReadDeadline and SetWriteDeadline methods of a net.Conn.
+type deadlineOnlyConn struct {
+	c net.Conn
+}
+
+func (c deadlineOnlyConn) Read(p []byte) (n int, err error)  { return c.c.Read(p) }
+func (c deadlineOnlyConn) Write(p []byte) (n int, err error) { return c.c.Write(p) }
+func (c deadlineOnlyConn) SetDeadline(t time.Time) error     { return c.c.SetDeadline(t) }
+func (c deadlineOnlyConn) Close() error                      { return c.c.Close() }
+
+// interruptibleConn only implements SetDeadline. Reads block until a deadline in the past is set. Once 10 bytes
+// have been written, the next Write blocks the same way, and later writes succeed. ReadFrom copies 10 bytes, then
+// consumes 10 more of which it writes 5 before blocking the same way, like a copy which is interrupted part way
+// through.
+type interruptibleConn struct {
+	out              bytes.Buffer
+	once             sync.Once
+	interrupted      chan struct{}
+	writeInterrupted bool
+}
+
+func (c *interruptibleConn) SetDeadline(t time.Time) error {
+	if !t.IsZero() && t.Before(time.Now()) {
+		c.once.Do(func() { close(c.interrupted) })
+	}
+	return nil
+}
+
+func (c *interruptibleConn) Read(p []byte) (n int, err error) {
+	<-c.interrupted
+	return 0, os.ErrDeadlineExceeded
+}
+
+func (c *interruptibleConn) Write(p []byte) (n int, err error) {
+	if c.out.Len() >= 10 && !c.writeInterrupted {
+		c.writeInterrupted = true
+		<-c.interrupted
+		return 0, os.ErrDeadlineExceeded
+	}
+	return c.out.Write(p)
+}
+
+func (c *interruptibleConn) ReadFrom(r io.Reader) (n int64, err error) {
+	p := make([]byte, 10)
+	if _, err := io.ReadFull(r, p); err != nil {
+		return n, err
+	}
+	c.out.Write(p)
+	n += 10
+	if _, err := io.ReadFull(r, p); err != nil {
+		return n, err
+	}
+	c.out.Write(p[:5])
+	n += 5
+	<-c.interrupted
+	return n, os.ErrDeadlineExceeded
+}
+
+// readDeadlineConn is a deadlineOnlyConn which also implements SetReadDeadline.
+type readDeadlineConn struct {
+	deadlineOnlyConn
+}
+
+func (c readDeadlineConn) SetReadDeadline(t time.Time) error { return c.c.SetReadDeadline(t) }
+
+func TestSetDeadlineOnly(t *testing.T) {
+	t.Run("fallback", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		// writes fall back to SetDeadline
+		w := WrapIO(readDeadlineConn{deadlineOnlyConn{c1}})
+		read, write := StrategyOf(w)
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, cancel)
+		_, err := w.(Writer).WriteContext(ctx, []byte("EXAMPLE"))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("read from", func(t *testing.T) {
+		c := &interruptibleConn{interrupted: make(chan struct{})}
+		rw := WrapIO(c)
+		data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+		type result struct {
+			n   int64
+			err error
+		}
+		// ReadFrom can't be resumed once a cancelled read interrupts it, so it isn't exposed
+		_, ok := rw.(ReaderFrom)
+		assert.False(t, ok)
+
+		copied := make(chan result, 1)
+		go func() {
+			n, err := Copy(context.Background(), rw.(Writer), NewReader(iotest.OneByteReader(bytes.NewReader(data))))
+			copied <- result{n, err}
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*50, cancel)
+		_, err := rw.(Reader).ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the interrupted write is retried, so the copy completes
+		res := <-copied
+		assert.NoError(t, res.err)
+		assert.Equal(t, int64(len(data)), res.n)
+		assert.Equal(t, string(data), c.out.String())
+	})
+	t.Run("cancel", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		rw := WrapIO(deadlineOnlyConn{c1})
+		read, write := StrategyOf(rw)
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+
+		// cancel a read while a write is in progress
+		writeErr := make(chan error, 1)
+		go func() {
+			_, err := rw.(Writer).WriteContext(context.Background(), []byte("EXAMPLE"))
+			writeErr <- err
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*100, cancel)
+		n, err := rw.(Reader).ReadContext(ctx, make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the write should not have been affected
+		require.NoError(t, c2.SetReadDeadline(time.Now().Add(time.Second)))
+		p := make([]byte, 7)
+		_, err = io.ReadFull(c2, p)
+		assert.NoError(t, err)
+		assert.Equal(t, "EXAMPLE", string(p))
+		assert.NoError(t, <-writeErr)
+	})
+	t.Run("stress", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		rw := WrapIO(deadlineOnlyConn{c1})
+		cr, cw := rw.(Reader), rw.(Writer)
+
+		outgoing := bytes.Repeat([]byte("0123456789abcdef"), 16*1024)
+		incoming := bytes.Repeat([]byte("fedcba9876543210"), 1024)
+
+		var wg sync.WaitGroup
+
+		// the peer echoes nothing, it just reads what we write and writes its own data slowly
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			p := make([]byte, len(outgoing))
+			_, err := io.ReadFull(c2, p)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(outgoing, p), "peer should receive the written data intact")
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < len(incoming); i += 1024 {
+				_, err := c2.Write(incoming[i : i+1024])
+				assert.NoError(t, err)
+				time.Sleep(time.Millisecond)
+			}
+		}()
+
+		// write everything with a context that is never cancelled
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < len(outgoing); i += 4096 {
+				n, err := cw.WriteContext(context.Background(), outgoing[i:i+4096])
+				assert.Equal(t, 4096, n)
+				if !assert.NoError(t, err) {
+					// unblock the peer
+					_ = c1.Close()
+					return
+				}
+			}
+		}()
+
+		// read everything with contexts that are frequently cancelled
+		var received []byte
+		p := make([]byte, 100)
+		for len(received) < len(incoming) {
+			ctx, clearTimeout := context.WithTimeout(context.Background(), time.Microsecond*500)
+			n, err := cr.ReadContext(ctx, p)
+			clearTimeout()
+			received = append(received, p[:n]...)
+			if err != nil {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			}
+		}
+		assert.True(t, bytes.Equal(incoming, received), "reader should receive the peer's data intact")
+
+		wg.Wait()
+	})
+}
@@ -17,28 +17,61 @@ func NewWriter(w io.Writer) Writer {
 	return WrapIO(w).(Writer)
 }
 
+// NewChunkedWriter creates a new contextaware.Writer from an existing io.Writer which splits each write into chunks of
+// at most chunkSize bytes and checks the context between them. If the context is cancelled part way through, the
+// number of bytes already written is returned along with the context's error. If chunkSize is <= 0 a default of
+// 32KiB is used.
+func NewChunkedWriter(w io.Writer, chunkSize int) Writer {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	return writerViaChunks{w, chunkSize}
+}
+
+// NewWriterWithOptions creates a new contextaware.Writer from an existing io.Writer using the given options. Unlike
+// NewWriter, only the io.Writer interface is wrapped.
+func NewWriterWithOptions(w io.Writer, opts ...Option) (Writer, error) {
+	return wrapWriter(w, newOptions(opts...))
+}
+
 // wrapWriter creates a new contextaware.Writer from an existing io.Writer.
-func wrapWriter(w io.Writer) Writer {
+func wrapWriter(w io.Writer, o *options) (Writer, error) {
 	if cw, ok := w.(Writer); ok {
-		return cw
-	}
-	if obj, ok := supportsSetWriteDeadline(w); ok {
-		return writerViaSetDeadline{w, obj.SetWriteDeadline}
+		return cw, nil
 	}
-	if obj, ok := supportsSetDeadline(w); ok {
-		return writerViaSetDeadline{w, obj.SetDeadline}
+	switch strategy := o.writeStrategy(); strategy {
+	case StrategyNone, StrategyDeadline:
+		if d, ok := o.writeDeadline(w); ok {
+			return writerViaSetDeadline{w, d}, nil
+		}
+		if strategy == StrategyDeadline {
+			return nil, errUnsupportedStrategy(strategy, "write to", w)
+		}
+		return writerViaWrite{w}, nil
+	case StrategyCheck:
+		return writerViaWrite{w}, nil
+	case StrategyChunked:
+		return writerViaChunks{w, o.chunkSize}, nil
+	case StrategyClose:
+		if c, ok := o.closeOnCancel(w); ok {
+			return writerViaClose{w, c}, nil
+		}
 	}
-	return writerViaWrite{w}
+	return nil, errUnsupportedStrategy(o.strategy, "write to", w)
 }
 
 type writerViaWrite struct {
 	io.Writer
 }
 
+func (w writerViaWrite) strategy() Strategy {
+	return StrategyCheck
+}
+
 func (w writerViaWrite) WriteContext(ctx context.Context, p []byte) (n int, err error) {
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return 0, canceled(ctx, "write", 0, nil)
 	default:
 	}
 	return w.Write(p)
@@ -46,11 +79,71 @@ func (w writerViaWrite) WriteContext(ctx context.Context, p []byte) (n int, err
 
 type writerViaSetDeadline struct {
 	io.Writer
-	setDeadline func(time.Time) error
+	d *deadline
+}
+
+func (w writerViaSetDeadline) strategy() Strategy {
+	return w.d.strategy()
+}
+
+func (w writerViaSetDeadline) unwrap() interface{} {
+	return w.Writer
+}
+
+func (w writerViaSetDeadline) writeDeadline() (*deadline, bool) {
+	return w.d, true
+}
+
+// SetWriteDeadline sets the deadline for future and pending writes. The deadline is combined with the deadline of
+// each context passed to WriteContext.
+func (w writerViaSetDeadline) SetWriteDeadline(t time.Time) error {
+	return w.d.SetDeadline(t)
+}
+
+// Write writes using the background context, so that the write is tracked along with any concurrent operations.
+func (w writerViaSetDeadline) Write(p []byte) (n int, err error) {
+	return w.WriteContext(context.Background(), p)
 }
 
 func (w writerViaSetDeadline) WriteContext(ctx context.Context, p []byte) (n int, err error) {
-	return withCancelViaDeadline(ctx, w.setDeadline, func() (int, error) {
-		return w.Write(p)
+	err = withCancelViaDeadline(ctx, w.d, func() error {
+		nw, err := w.Writer.Write(p[n:])
+		n += nw
+		return err
 	})
+	return n, annotate(err, "write", int64(n))
+}
+
+type writerViaChunks struct {
+	io.Writer
+	chunkSize int
+}
+
+func (w writerViaChunks) strategy() Strategy {
+	return StrategyChunked
+}
+
+func (w writerViaChunks) WriteContext(ctx context.Context, p []byte) (n int, err error) {
+	for once := true; once || len(p) > 0; once = false {
+		select {
+		case <-ctx.Done():
+			return n, canceled(ctx, "write", n, nil)
+		default:
+		}
+
+		chunk := p
+		if len(chunk) > w.chunkSize {
+			chunk = chunk[:w.chunkSize]
+		}
+		nw, err := w.Write(chunk)
+		n += nw
+		if err != nil {
+			return n, err
+		}
+		if nw != len(chunk) {
+			return n, io.ErrShortWrite
+		}
+		p = p[nw:]
+	}
+	return n, nil
 }
@@ -8,7 +8,7 @@ import (
 // A WriterAt is an io.WriterAt that also supports cancellation via a context.Context.
 type WriterAt interface {
 	io.WriterAt
-	ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error)
+	WriteAtContext(ctx context.Context, p []byte, off int64) (n int, err error)
 }
 
 // NewWriterAt creates a contextaware.WriterAt from an existing io.WriterAt.
@@ -16,22 +16,118 @@ func NewWriterAt(wa io.WriterAt) WriterAt {
 	return WrapIO(wa).(WriterAt)
 }
 
-func wrapWriterAt(ra io.WriterAt) WriterAt {
-	if cra, ok := ra.(WriterAt); ok {
-		return cra
+func wrapWriterAt(wa io.WriterAt, o *options) (WriterAt, error) {
+	if cwa, ok := wa.(WriterAt); ok {
+		return cwa, nil
 	}
-	return writerViaWriteAt{ra}
+	switch strategy := o.writeStrategy(); strategy {
+	case StrategyNone, StrategyDeadline:
+		if d, ok := o.writeDeadline(wa); ok {
+			return writerAtViaSetDeadline{wa, d}, nil
+		}
+		if strategy == StrategyDeadline {
+			return nil, errUnsupportedStrategy(strategy, "write at", wa)
+		}
+		return writerAtViaWriteAt{wa}, nil
+	case StrategyCheck:
+		return writerAtViaWriteAt{wa}, nil
+	case StrategyChunked:
+		return writerAtViaChunks{wa, o.chunkSize}, nil
+	case StrategyClose:
+		if c, ok := o.closeOnCancel(wa); ok {
+			return writerAtViaClose{wa, c}, nil
+		}
+	}
+	return nil, errUnsupportedStrategy(o.strategy, "write at", wa)
 }
 
-type writerViaWriteAt struct {
+type writerAtViaWriteAt struct {
 	io.WriterAt
 }
 
-func (wa writerViaWriteAt) ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+func (wa writerAtViaWriteAt) strategy() Strategy {
+	return StrategyCheck
+}
+
+func (wa writerAtViaWriteAt) WriteAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
 	select {
 	case <-ctx.Done():
-		return 0, ctx.Err()
+		return 0, canceled(ctx, "write", 0, nil)
 	default:
 	}
 	return wa.WriteAt(p, off)
 }
+
+type writerAtViaSetDeadline struct {
+	io.WriterAt
+	d *deadline
+}
+
+func (wa writerAtViaSetDeadline) strategy() Strategy {
+	return wa.d.strategy()
+}
+
+// WriteAt writes using the background context, so that the write is tracked along with any concurrent operations.
+func (wa writerAtViaSetDeadline) WriteAt(p []byte, off int64) (n int, err error) {
+	return wa.WriteAtContext(context.Background(), p, off)
+}
+
+func (wa writerAtViaSetDeadline) WriteAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	err = withCancelViaDeadline(ctx, wa.d, func() error {
+		nw, err := wa.WriterAt.WriteAt(p[n:], off+int64(n))
+		n += nw
+		return err
+	})
+	return n, annotate(err, "write", int64(n))
+}
+
+type writerAtViaChunks struct {
+	io.WriterAt
+	chunkSize int
+}
+
+func (wa writerAtViaChunks) strategy() Strategy {
+	return StrategyChunked
+}
+
+func (wa writerAtViaChunks) WriteAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	for once := true; once || len(p) > 0; once = false {
+		select {
+		case <-ctx.Done():
+			return n, canceled(ctx, "write", n, nil)
+		default:
+		}
+		chunk := p
+		if len(chunk) > wa.chunkSize {
+			chunk = chunk[:wa.chunkSize]
+		}
+		nw, err := wa.WriteAt(chunk, off)
+		n += nw
+		if err != nil {
+			return n, err
+		}
+		if nw != len(chunk) {
+			return n, io.ErrShortWrite
+		}
+		p = p[nw:]
+		off += int64(nw)
+	}
+	return n, nil
+}
+
+type writerAtViaClose struct {
+	io.WriterAt
+	c *closeOnCancel
+}
+
+func (wa writerAtViaClose) strategy() Strategy {
+	return StrategyClose
+}
+
+func (wa writerAtViaClose) WriteAtContext(ctx context.Context, p []byte, off int64) (n int, err error) {
+	err = wa.c.withCancelViaClose(ctx, func() (err error) {
+		n, err = wa.WriteAt(p, off)
+		return err
+	})
+	return n, annotate(err, "write", int64(n))
+}
@@ -0,0 +1,105 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+)
+
+// A WriterTo is an io.WriterTo that also supports cancellation via a context.Context.
+type WriterTo interface {
+	io.WriterTo
+	WriteToContext(ctx context.Context, w Writer) (n int64, err error)
+}
+
+// NewWriterTo creates a contextaware.WriterTo from an existing io.WriterTo.
+func NewWriterTo(wt io.WriterTo) WriterTo {
+	return WrapIO(wt).(WriterTo)
+}
+
+func wrapWriterTo(wt io.WriterTo, o *options) (WriterTo, error) {
+	if cwt, ok := wt.(WriterTo); ok {
+		return cwt, nil
+	}
+	switch o.strategy {
+	case StrategyBackground, StrategyChunked, StrategyClose:
+		// WriteTo would bypass the strategy, so leave it to Copy to use ReadContext instead
+		return nil, nil
+	case StrategyNone, StrategyDeadline:
+		if d, ok := o.readDeadline(wt); ok {
+			if d.coordinator != nil {
+				// a cancelled write would interrupt WriteTo, which can't be resumed, so leave it to Copy to use
+				// ReadContext instead
+				return nil, nil
+			}
+			return writerToViaSetDeadline{wt, d}, nil
+		}
+		if o.strategy == StrategyDeadline {
+			return nil, errUnsupportedStrategy(o.strategy, "write from", wt)
+		}
+		return writerToViaWriteTo{wt}, nil
+	case StrategyCheck:
+		return writerToViaWriteTo{wt}, nil
+	}
+	return nil, errUnsupportedStrategy(o.strategy, "write from", wt)
+}
+
+type writerToViaWriteTo struct {
+	io.WriterTo
+}
+
+func (wt writerToViaWriteTo) strategy() Strategy {
+	return StrategyCheck
+}
+
+func (wt writerToViaWriteTo) WriteToContext(ctx context.Context, w Writer) (n int64, err error) {
+	select {
+	case <-ctx.Done():
+		return 0, canceled(ctx, "writeto", 0, nil)
+	default:
+	}
+	return wt.WriteTo(contextWriter{ctx, w})
+}
+
+type writerToViaSetDeadline struct {
+	io.WriterTo
+	d *deadline
+}
+
+func (wt writerToViaSetDeadline) strategy() Strategy {
+	return wt.d.strategy()
+}
+
+// WriteTo writes using the background context, so that the operation is tracked along with any concurrent
+// operations.
+func (wt writerToViaSetDeadline) WriteTo(w io.Writer) (n int64, err error) {
+	err = withCancelViaDeadlineOnce(context.Background(), wt.d, func() error {
+		nw, err := wt.WriterTo.WriteTo(w)
+		n += nw
+		return err
+	})
+	return n, annotate(err, "writeto", n)
+}
+
+func (wt writerToViaSetDeadline) WriteToContext(ctx context.Context, w Writer) (n int64, err error) {
+	// If the destination also supports deadlines, hand the underlying object to WriteTo so that the stdlib can use
+	// splice or sendfile, and cancel both sides via their deadlines.
+	if dst, ok := unwrap(w).(io.Writer); ok {
+		if dstDeadline, ok := writeDeadlineOf(w); ok {
+			err = withCancelViaDeadlineOnce(ctx, wt.d, func() error {
+				return withCancelViaDeadlineOnce(ctx, dstDeadline, func() error {
+					nw, err := wt.WriterTo.WriteTo(dst)
+					n += nw
+					return err
+				})
+			})
+			return n, annotate(err, "writeto", n)
+		}
+	}
+
+	err = withCancelViaDeadlineOnce(ctx, wt.d, func() error {
+		nw, err := wt.WriterTo.WriteTo(contextWriter{ctx, w})
+		n += nw
+		return err
+	})
+	return n, annotate(err, "writeto", n)
+}
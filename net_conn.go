@@ -0,0 +1,152 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+	"net"
+)
+
+// A Conn is a net.Conn that also supports cancellation via a context.Context.
+type Conn interface {
+	net.Conn
+	ReadContext(ctx context.Context, p []byte) (n int, err error)
+	WriteContext(ctx context.Context, p []byte) (n int, err error)
+	CloseContext(ctx context.Context) error
+}
+
+// WrapConn creates a new contextaware.Conn from an existing net.Conn. Reads and writes are cancelled via the
+// connection's deadlines. If the connection doesn't support deadlines, it is closed when a context is cancelled
+// instead (see StrategyClose).
+//
+// The read and write deadlines of the returned Conn are combined with the deadline of each context, so they must be
+// set through it rather than on the original connection.
+func WrapConn(c net.Conn) Conn {
+	if cc, ok := c.(Conn); ok {
+		return cc
+	}
+
+	o := newOptions()
+	w := wrapped{obj: c, opts: o}
+	cc := &conn{c: c}
+	cl, _ := o.closeOnCancel(c)
+	if d, ok := o.readDeadline(c); ok {
+		d.setFallback(StrategyClose, cl)
+		cc.Reader = readerViaSetDeadline{c, d}
+	} else {
+		cc.Reader = readerViaClose{c, cl}
+	}
+	if d, ok := o.writeDeadline(c); ok {
+		d.setFallback(StrategyClose, cl)
+		cc.Writer = writerViaSetDeadline{c, d}
+	} else {
+		cc.Writer = writerViaClose{c, cl}
+	}
+	w.add(cc.Reader)
+	w.add(cc.Writer)
+	cc.wrapped = w
+
+	// Keep ReadFrom and WriteTo when the connection supports them, so that copies through it can still use splice
+	// or sendfile. They're cancelled via the connection's deadlines, so they're only kept when it supports them.
+	var rf ReaderFrom
+	if crf, ok := c.(io.ReaderFrom); ok {
+		if d, ok := o.writeDeadline(c); ok {
+			rf = readerFromViaSetDeadline{crf, d}
+		}
+	}
+	var wt WriterTo
+	if cwt, ok := c.(io.WriterTo); ok {
+		if d, ok := o.readDeadline(c); ok {
+			wt = writerToViaSetDeadline{cwt, d}
+		}
+	}
+	switch {
+	case rf != nil && wt != nil:
+		return connReaderFromWriterTo{cc, rf, wt}
+	case rf != nil:
+		return connReaderFrom{cc, rf}
+	case wt != nil:
+		return connWriterTo{cc, wt}
+	default:
+		return cc
+	}
+}
+
+type conn struct {
+	Reader
+	Writer
+	wrapped
+	c net.Conn
+}
+
+type connReaderFrom struct {
+	*conn
+	ReaderFrom
+}
+
+type connWriterTo struct {
+	*conn
+	WriterTo
+}
+
+type connReaderFromWriterTo struct {
+	*conn
+	ReaderFrom
+	WriterTo
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return c.c.LocalAddr()
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.c.RemoteAddr()
+}
+
+func (c *conn) Close() error {
+	return c.c.Close()
+}
+
+// CloseContext closes the connection. Any blocked reads or writes are unblocked. If closing the connection involves
+// writing, such as the close_notify alert sent by a tls.Conn, the write is cancelled when the context is. The
+// connection is closed even if the context is already done, in which case a *CanceledError is returned.
+func (c *conn) CloseContext(ctx context.Context) error {
+	d, ok := c.writeDeadline()
+	if !ok {
+		return c.c.Close()
+	}
+
+	closed := false
+	err := withCancelViaDeadline(ctx, d, func() error {
+		closed = true
+		return c.c.Close()
+	})
+	if !closed {
+		// The close wasn't attempted, either because the context was already done or because the deadline couldn't
+		// be set, so close without waiting for any writes.
+		d.cancel()
+		err = c.c.Close()
+		if ctx.Err() != nil {
+			err = canceled(ctx, "close", 0, err)
+		}
+	}
+	return annotate(err, "close", 0)
+}
+
+// A Dialer is a net.Dialer which returns context-aware connections.
+type Dialer struct {
+	net.Dialer
+}
+
+// Dial connects to the address on the named network. See net.Dialer.Dial.
+func (d *Dialer) Dial(network, address string) (Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address on the named network using the provided context. See net.Dialer.DialContext.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (Conn, error) {
+	c, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return WrapConn(c), nil
+}
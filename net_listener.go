@@ -0,0 +1,94 @@
+package contextaware
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+)
+
+// A Listener is a net.Listener that also supports cancellation via a context.Context. The connections it accepts are
+// context-aware.
+type Listener interface {
+	net.Listener
+	AcceptContext(ctx context.Context) (Conn, error)
+}
+
+// WrapListener creates a new contextaware.Listener from an existing net.Listener. Calls to AcceptContext are
+// cancelled via the listener's SetDeadline method, which is supported by *net.TCPListener and *net.UnixListener. If
+// the listener doesn't support deadlines, it is closed when a context is cancelled instead, after which every call
+// to Accept fails with ErrClosedByCancel.
+func WrapListener(l net.Listener) Listener {
+	if cl, ok := l.(Listener); ok {
+		return cl
+	}
+	c := &closeOnCancel{closer: l}
+	if wsd, ok := supportsSetDeadline(l); ok {
+		d := newDeadline(wsd.SetDeadline)
+		d.setFallback(StrategyClose, c)
+		return listenerViaSetDeadline{l, d}
+	}
+	return listenerViaClose{l, c}
+}
+
+type listenerViaSetDeadline struct {
+	net.Listener
+	d *deadline
+}
+
+func (l listenerViaSetDeadline) unwrap() interface{} {
+	return l.Listener
+}
+
+// SetDeadline sets the deadline for future and pending calls to Accept. The deadline is combined with the deadline of
+// each context passed to AcceptContext.
+func (l listenerViaSetDeadline) SetDeadline(t time.Time) error {
+	return l.d.SetDeadline(t)
+}
+
+// Accept accepts a connection using the background context, so that it is tracked along with any concurrent calls.
+func (l listenerViaSetDeadline) Accept() (net.Conn, error) {
+	return l.AcceptContext(context.Background())
+}
+
+func (l listenerViaSetDeadline) AcceptContext(ctx context.Context) (c Conn, err error) {
+	err = withCancelViaDeadline(ctx, l.d, func() error {
+		nc, err := l.Listener.Accept()
+		if err != nil {
+			return err
+		}
+		c = WrapConn(nc)
+		return nil
+	})
+	return c, annotate(err, "accept", 0)
+}
+
+type listenerViaClose struct {
+	net.Listener
+	c *closeOnCancel
+}
+
+func (l listenerViaClose) unwrap() interface{} {
+	return l.Listener
+}
+
+// SetDeadline returns os.ErrNoDeadline since the listener doesn't support deadlines.
+func (l listenerViaClose) SetDeadline(t time.Time) error {
+	return os.ErrNoDeadline
+}
+
+func (l listenerViaClose) Accept() (net.Conn, error) {
+	return l.AcceptContext(context.Background())
+}
+
+func (l listenerViaClose) AcceptContext(ctx context.Context) (c Conn, err error) {
+	err = l.c.withCancelViaClose(ctx, func() error {
+		nc, err := l.Listener.Accept()
+		if err != nil {
+			return err
+		}
+		c = WrapConn(nc)
+		return nil
+	})
+	return c, annotate(err, "accept", 0)
+}
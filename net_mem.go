@@ -0,0 +1,148 @@
+package contextaware
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// A MemListener is an in-memory Listener. Connections are made by calling DialContext, and consist of a pair of
+// PipeConns. It is useful for testing network code without real sockets.
+type MemListener struct {
+	opts  *pipeOptions
+	conns chan *queuedConn // accept queue
+
+	mu     sync.Mutex // guards closed
+	closed bool
+	done   chan struct{}
+}
+
+// NewMemListener creates a new MemListener. Up to backlog dialed connections are queued waiting to be accepted,
+// after which DialContext blocks. The options configure each connection (see ConnPipe), except that for WithAddrs the
+// first address is used by dialed connections and the second is the listener's address.
+func NewMemListener(backlog int, opts ...PipeOption) *MemListener {
+	if backlog < 0 {
+		backlog = 0
+	}
+	return &MemListener{
+		opts:  newPipeOptions(opts...),
+		conns: make(chan *queuedConn, backlog),
+		done:  make(chan struct{}),
+	}
+}
+
+// Addr returns the listener's network address.
+func (l *MemListener) Addr() net.Addr {
+	return l.opts.addrs[1]
+}
+
+// Accept waits for and returns the next connection using the background context.
+func (l *MemListener) Accept() (net.Conn, error) {
+	return l.AcceptContext(context.Background())
+}
+
+// AcceptContext waits for and returns the next connection. Once the listener has been closed, AcceptContext fails
+// with an error wrapping net.ErrClosed.
+func (l *MemListener) AcceptContext(ctx context.Context) (Conn, error) {
+	if isClosedChan(l.done) {
+		return nil, l.closedError("accept")
+	}
+
+	for {
+		select {
+		case q := <-l.conns:
+			if q.claim(connAccepted) {
+				return q.c, nil
+			}
+			// the dialer gave up on the connection because the listener was closed
+		case <-l.done:
+			return nil, l.closedError("accept")
+		case <-ctx.Done():
+			return nil, canceled(ctx, "accept", 0, nil)
+		}
+	}
+}
+
+// DialContext connects to the listener. The network and address are ignored, so that DialContext can be used as
+// http.Transport.DialContext. If the listener's backlog is full, DialContext blocks until a connection is accepted.
+// Once the listener has been closed, pending and future calls fail with an error wrapping net.ErrClosed.
+func (l *MemListener) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if isClosedChan(l.done) {
+		return nil, l.closedError("dial")
+	}
+
+	client, server := newConnPipe(l.opts)
+	q := &queuedConn{c: server}
+	select {
+	case l.conns <- q:
+	case <-l.done:
+		return nil, l.closedError("dial")
+	case <-ctx.Done():
+		return nil, canceled(ctx, "dial", 0, nil)
+	}
+
+	if isClosedChan(l.done) {
+		// The listener was closed while the connection was being queued, so Close may have missed it. Unless it has
+		// already been accepted, drop it.
+		l.drain()
+		if q.claim(connDropped) {
+			_ = server.Close()
+		}
+	}
+	if atomic.LoadInt32(&q.state) == connDropped {
+		_ = client.Close()
+		return nil, l.closedError("dial")
+	}
+	return client, nil
+}
+
+// Close closes the listener. Pending calls to AcceptContext and DialContext are unblocked and return errors, and
+// connections which were queued but not accepted are closed.
+func (l *MemListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return l.closedError("close")
+	}
+	l.closed = true
+	close(l.done)
+	l.drain()
+	return nil
+}
+
+// drain closes the connections in the accept queue.
+func (l *MemListener) drain() {
+	for {
+		select {
+		case q := <-l.conns:
+			if q.claim(connDropped) {
+				_ = q.c.Close()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *MemListener) closedError(op string) error {
+	return &net.OpError{Op: op, Net: l.Addr().Network(), Addr: l.Addr(), Err: net.ErrClosed}
+}
+
+const (
+	connQueued int32 = iota
+	connAccepted
+	connDropped
+)
+
+// A queuedConn is the server end of a dialed connection in the accept queue. It is claimed by whichever of
+// AcceptContext, DialContext or Close gets to it first, so a connection is either accepted or dropped, never both.
+type queuedConn struct {
+	c     *PipeConn
+	state int32 // connQueued, connAccepted or connDropped
+}
+
+func (q *queuedConn) claim(state int32) bool {
+	return atomic.CompareAndSwapInt32(&q.state, connQueued, state)
+}
@@ -0,0 +1,153 @@
+package contextaware
+
+import (
+	"context"
+	"net"
+)
+
+// A PacketConn is a net.PacketConn that also supports cancellation via a context.Context.
+type PacketConn interface {
+	net.PacketConn
+	ReadFromContext(ctx context.Context, p []byte) (n int, addr net.Addr, err error)
+	WriteToContext(ctx context.Context, p []byte, addr net.Addr) (n int, err error)
+}
+
+// WrapPacketConn creates a new contextaware.PacketConn from an existing net.PacketConn. Like WrapConn, reads and
+// writes are cancelled via the connection's deadlines, or by closing the connection if it doesn't support them. If c
+// is a *net.UDPConn, the returned PacketConn is a *UDPConn.
+func WrapPacketConn(c net.PacketConn) PacketConn {
+	if pc, ok := c.(PacketConn); ok {
+		return pc
+	}
+	if udp, ok := c.(*net.UDPConn); ok {
+		return WrapUDPConn(udp)
+	}
+	return newPacketConn(c)
+}
+
+type packetConn struct {
+	wrapped
+	c      net.PacketConn
+	rd, wd *deadline
+	closer *closeOnCancel
+}
+
+func newPacketConn(c net.PacketConn) *packetConn {
+	o := newOptions()
+	pc := &packetConn{c: c}
+	pc.wrapped = wrapped{obj: c, opts: o}
+	pc.closer, _ = o.closeOnCancel(c)
+	if d, ok := o.readDeadline(c); ok {
+		d.setFallback(StrategyClose, pc.closer)
+		pc.rd = d
+	}
+	if d, ok := o.writeDeadline(c); ok {
+		d.setFallback(StrategyClose, pc.closer)
+		pc.wd = d
+	}
+	return pc
+}
+
+func (pc *packetConn) strategies() (read, write Strategy) {
+	read, write = StrategyClose, StrategyClose
+	if pc.rd != nil {
+		read = pc.rd.strategy()
+	}
+	if pc.wd != nil {
+		write = pc.wd.strategy()
+	}
+	return read, write
+}
+
+// withCancel runs an operation, cancelling it via d if the connection implements deadlines in that direction, or by
+// closing the connection if it doesn't. If the deadline turns out to be unsupported, d falls back to closing it.
+func (pc *packetConn) withCancel(ctx context.Context, d *deadline, operation func() error) error {
+	if d != nil {
+		return withCancelViaDeadline(ctx, d, operation)
+	}
+	return pc.closer.withCancelViaClose(ctx, operation)
+}
+
+func (pc *packetConn) LocalAddr() net.Addr {
+	return pc.c.LocalAddr()
+}
+
+func (pc *packetConn) Close() error {
+	return pc.c.Close()
+}
+
+// ReadFrom reads a packet using the background context, so that the read is tracked along with any concurrent
+// operations.
+func (pc *packetConn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	return pc.ReadFromContext(context.Background(), p)
+}
+
+func (pc *packetConn) ReadFromContext(ctx context.Context, p []byte) (n int, addr net.Addr, err error) {
+	err = pc.withCancel(ctx, pc.rd, func() (err error) {
+		n, addr, err = pc.c.ReadFrom(p)
+		return err
+	})
+	return n, addr, annotate(err, "read", int64(n))
+}
+
+// WriteTo writes a packet using the background context, so that the write is tracked along with any concurrent
+// operations.
+func (pc *packetConn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	return pc.WriteToContext(context.Background(), p, addr)
+}
+
+func (pc *packetConn) WriteToContext(ctx context.Context, p []byte, addr net.Addr) (n int, err error) {
+	err = pc.withCancel(ctx, pc.wd, func() (err error) {
+		n, err = pc.c.WriteTo(p, addr)
+		return err
+	})
+	return n, annotate(err, "write", int64(n))
+}
+
+// A UDPConn is a context-aware *net.UDPConn.
+type UDPConn struct {
+	*packetConn
+	udp *net.UDPConn
+}
+
+// WrapUDPConn creates a new contextaware.UDPConn from an existing *net.UDPConn.
+func WrapUDPConn(c *net.UDPConn) *UDPConn {
+	return &UDPConn{newPacketConn(c), c}
+}
+
+// ReadMsgUDP reads a message using the background context. See ReadMsgUDPContext.
+func (c *UDPConn) ReadMsgUDP(b, oob []byte) (n, oobn, flags int, addr *net.UDPAddr, err error) {
+	return c.ReadMsgUDPContext(context.Background(), b, oob)
+}
+
+// ReadMsgUDPContext reads a message from c, copying the payload into b and the associated out-of-band data into
+// oob. See net.UDPConn.ReadMsgUDP.
+func (c *UDPConn) ReadMsgUDPContext(
+	ctx context.Context,
+	b, oob []byte,
+) (n, oobn, flags int, addr *net.UDPAddr, err error) {
+	err = c.withCancel(ctx, c.rd, func() (err error) {
+		n, oobn, flags, addr, err = c.udp.ReadMsgUDP(b, oob)
+		return err
+	})
+	return n, oobn, flags, addr, annotate(err, "read", int64(n))
+}
+
+// WriteMsgUDP writes a message using the background context. See WriteMsgUDPContext.
+func (c *UDPConn) WriteMsgUDP(b, oob []byte, addr *net.UDPAddr) (n, oobn int, err error) {
+	return c.WriteMsgUDPContext(context.Background(), b, oob, addr)
+}
+
+// WriteMsgUDPContext writes a message to addr, or to c's remote address if c is connected, copying the payload from
+// b and the associated out-of-band data from oob. See net.UDPConn.WriteMsgUDP.
+func (c *UDPConn) WriteMsgUDPContext(
+	ctx context.Context,
+	b, oob []byte,
+	addr *net.UDPAddr,
+) (n, oobn int, err error) {
+	err = c.withCancel(ctx, c.wd, func() (err error) {
+		n, oobn, err = c.udp.WriteMsgUDP(b, oob, addr)
+		return err
+	})
+	return n, oobn, annotate(err, "write", int64(n))
+}
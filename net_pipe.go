@@ -0,0 +1,126 @@
+package contextaware
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// A PipeOption configures the connections created by ConnPipe.
+type PipeOption func(*pipeOptions)
+
+type pipeOptions struct {
+	addrs      [2]net.Addr
+	bufferSize int
+}
+
+func newPipeOptions(opts ...PipeOption) *pipeOptions {
+	o := &pipeOptions{addrs: [2]net.Addr{pipeAddr{}, pipeAddr{}}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithAddrs sets the addresses of the connections created by ConnPipe. a is the local address of the first
+// connection and the remote address of the second, and b is the reverse.
+func WithAddrs(a, b net.Addr) PipeOption {
+	return func(o *pipeOptions) {
+		o.addrs = [2]net.Addr{a, b}
+	}
+}
+
+// WithBufferSize sets the size of the buffer used in each direction by ConnPipe. Writes complete as soon as the data
+// fits in the buffer (see BufferedPipe). By default the connections are synchronous, like net.Pipe.
+func WithBufferSize(size int) PipeOption {
+	return func(o *pipeOptions) {
+		o.bufferSize = size
+	}
+}
+
+// pipeAddr is the default address of a PipeConn.
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+// ConnPipe creates a full-duplex, in-memory, context-aware network connection. Both ends implement net.Conn and
+// contextaware.Conn. It is modeled after net.Pipe.
+func ConnPipe(opts ...PipeOption) (*PipeConn, *PipeConn) {
+	return newConnPipe(newPipeOptions(opts...))
+}
+
+func newConnPipe(o *pipeOptions) (*PipeConn, *PipeConn) {
+	r1, w1 := BufferedPipe(o.bufferSize)
+	r2, w2 := BufferedPipe(o.bufferSize)
+	return &PipeConn{r1, w2, o.addrs[0], o.addrs[1]},
+		&PipeConn{r2, w1, o.addrs[1], o.addrs[0]}
+}
+
+// A PipeConn is one end of a connection created by ConnPipe.
+type PipeConn struct {
+	r      *PipeReader
+	w      *PipeWriter
+	local  net.Addr
+	remote net.Addr
+}
+
+func (c *PipeConn) Read(p []byte) (n int, err error) {
+	return c.r.Read(p)
+}
+
+func (c *PipeConn) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	return c.r.ReadContext(ctx, p)
+}
+
+func (c *PipeConn) Write(p []byte) (n int, err error) {
+	return c.w.Write(p)
+}
+
+func (c *PipeConn) WriteContext(ctx context.Context, p []byte) (n int, err error) {
+	return c.w.WriteContext(ctx, p)
+}
+
+func (c *PipeConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *PipeConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// SetDeadline sets the read and write deadlines. See PipeReader.SetReadDeadline and PipeWriter.SetWriteDeadline.
+func (c *PipeConn) SetDeadline(t time.Time) error {
+	_ = c.r.SetReadDeadline(t)
+	return c.w.SetWriteDeadline(t)
+}
+
+func (c *PipeConn) SetReadDeadline(t time.Time) error {
+	return c.r.SetReadDeadline(t)
+}
+
+func (c *PipeConn) SetWriteDeadline(t time.Time) error {
+	return c.w.SetWriteDeadline(t)
+}
+
+// CloseRead shuts down the reading side of the connection. Writes by the other end fail with io.ErrClosedPipe.
+func (c *PipeConn) CloseRead() error {
+	return c.r.Close()
+}
+
+// CloseWrite shuts down the writing side of the connection. Once any buffered data has been read, reads by the other
+// end return io.EOF.
+func (c *PipeConn) CloseWrite() error {
+	return c.w.Close()
+}
+
+// Close closes both sides of the connection.
+func (c *PipeConn) Close() error {
+	_ = c.r.Close()
+	return c.w.Close()
+}
+
+// CloseContext closes both sides of the connection. Since closing never blocks, the context is ignored.
+func (c *PipeConn) CloseContext(ctx context.Context) error {
+	return c.Close()
+}
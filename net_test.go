@@ -0,0 +1,523 @@
+package contextaware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noDeadlineConn is a net.Conn which doesn't support deadlines.
+type noDeadlineConn struct {
+	net.Conn
+}
+
+func (noDeadlineConn) SetDeadline(time.Time) error      { return os.ErrNoDeadline }
+func (noDeadlineConn) SetReadDeadline(time.Time) error  { return os.ErrNoDeadline }
+func (noDeadlineConn) SetWriteDeadline(time.Time) error { return os.ErrNoDeadline }
+
+func TestConn(t *testing.T) {
+	t.Run("addrs", func(t *testing.T) {
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+		defer c2.Close()
+
+		c := WrapConn(c2)
+		assert.Equal(t, c2.LocalAddr(), c.LocalAddr())
+		assert.Equal(t, c2.RemoteAddr(), c.RemoteAddr())
+		assert.Equal(t, c, WrapConn(c))
+
+		read, write := StrategyOf(c)
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+		defer c2.Close()
+
+		c := WrapConn(c2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err := c.ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// the connection is still usable
+		_, err = c1.Write([]byte{1, 2, 3, 4})
+		require.NoError(t, err)
+		p := make([]byte, 4)
+		n, err := c.Read(p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+	})
+	t.Run("user deadline", func(t *testing.T) {
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+		defer c2.Close()
+
+		c := WrapConn(c2)
+		require.NoError(t, c.SetDeadline(time.Now().Add(50*time.Millisecond)))
+		_, err := c.ReadContext(context.Background(), make([]byte, 4))
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("close", func(t *testing.T) {
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := WrapConn(c2)
+		err := c.CloseContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		var ce *CanceledError
+		if assert.True(t, errors.As(err, &ce)) {
+			assert.Equal(t, "close", ce.Op)
+		}
+
+		_, err = c.Read(make([]byte, 4))
+		assert.ErrorIs(t, err, net.ErrClosed)
+
+		c3, c4 := tcpPair(t)
+		defer c3.Close()
+		assert.NoError(t, WrapConn(c4).CloseContext(context.Background()))
+	})
+	t.Run("copy", func(t *testing.T) {
+		// the conns keep ReadFrom and WriteTo, so the copy can use splice
+		c1, c2 := tcpPair(t)
+		defer c1.Close()
+		defer c2.Close()
+		c3, c4 := tcpPair(t)
+		defer c3.Close()
+		defer c4.Close()
+
+		src, dst := WrapConn(c2), WrapConn(c3)
+		assert.Implements(t, (*ReaderFrom)(nil), dst)
+		assert.Implements(t, (*WriterTo)(nil), src)
+
+		data := []byte("hello world")
+		go func() {
+			_, _ = c1.Write(data)
+			_ = c1.Close()
+		}()
+		received := make(chan []byte, 1)
+		go func() {
+			p, _ := io.ReadAll(c4)
+			received <- p
+		}()
+		n, err := Copy(context.Background(), dst, src)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+		_ = c3.Close()
+		assert.Equal(t, data, <-received)
+
+		// the copy is cancelled via the deadlines
+		c5, c6 := tcpPair(t)
+		defer c5.Close()
+		defer c6.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err = Copy(ctx, WrapConn(c5), WrapConn(c6))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("no deadline", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+
+		// the conn can only turn out not to support deadlines once one is set
+		c := WrapConn(noDeadlineConn{c2})
+		read, write := StrategyOf(c)
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+		assert.ErrorIs(t, c.SetDeadline(time.Now()), os.ErrNoDeadline)
+		read, write = StrategyOf(c)
+		assert.Equal(t, StrategyClose, read)
+		assert.Equal(t, StrategyClose, write)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err := c.ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = c.WriteContext(context.Background(), []byte{1})
+		assert.ErrorIs(t, err, ErrClosedByCancel)
+	})
+}
+
+func TestDialer(t *testing.T) {
+	li, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer li.Close()
+
+	go func() {
+		c, err := li.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _ = c.Write([]byte{1, 2, 3, 4})
+	}()
+
+	var d Dialer
+	c, err := d.DialContext(context.Background(), "tcp", li.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+
+	assert.Equal(t, li.Addr(), c.RemoteAddr())
+	p := make([]byte, 4)
+	n, err := c.ReadContext(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = d.DialContext(ctx, "tcp", li.Addr().String())
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// plainListener hides the SetDeadline method of a net.Listener.
+type plainListener struct {
+	net.Listener
+}
+
+func TestListener(t *testing.T) {
+	t.Run("deadline", func(t *testing.T) {
+		nl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		l := WrapListener(nl)
+		defer l.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err = l.AcceptContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		var ce *CanceledError
+		if assert.True(t, errors.As(err, &ce)) {
+			assert.Equal(t, "accept", ce.Op)
+		}
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		_, err = l.AcceptContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// the listener is still usable
+		go func() {
+			c, err := net.Dial("tcp", nl.Addr().String())
+			if err == nil {
+				_, _ = c.Write([]byte{1, 2, 3, 4})
+				c.Close()
+			}
+		}()
+		c, err := l.AcceptContext(context.Background())
+		require.NoError(t, err)
+		defer c.Close()
+		p := make([]byte, 4)
+		n, err := c.ReadContext(context.Background(), p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+	})
+	t.Run("user deadline", func(t *testing.T) {
+		nl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		l := WrapListener(nl)
+		defer l.Close()
+
+		require.NoError(t, l.(interface{ SetDeadline(time.Time) error }).SetDeadline(time.Now().Add(50*time.Millisecond)))
+		_, err = l.Accept()
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("close", func(t *testing.T) {
+		nl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		l := WrapListener(plainListener{nl})
+		defer l.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err = l.AcceptContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = l.Accept()
+		assert.ErrorIs(t, err, ErrClosedByCancel)
+	})
+}
+
+func TestPacketConn(t *testing.T) {
+	t.Run("udp", func(t *testing.T) {
+		c1, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		c2, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		pc1, pc2 := WrapPacketConn(c1), WrapPacketConn(c2)
+		defer pc1.Close()
+		defer pc2.Close()
+		assert.IsType(t, &UDPConn{}, pc1)
+
+		read, write := StrategyOf(pc1)
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, _, err = pc2.ReadFromContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		_, _, err = pc2.ReadFromContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		n, err := pc1.WriteToContext(context.Background(), []byte{1, 2, 3, 4}, pc2.LocalAddr())
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+		p := make([]byte, 4)
+		n, addr, err := pc2.ReadFromContext(context.Background(), p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+		assert.Equal(t, pc1.LocalAddr(), addr)
+	})
+	t.Run("msg", func(t *testing.T) {
+		c1, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		require.NoError(t, err)
+		c2, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		require.NoError(t, err)
+
+		uc1, uc2 := WrapUDPConn(c1), WrapUDPConn(c2)
+		defer uc1.Close()
+		defer uc2.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, _, _, _, err = uc2.ReadMsgUDPContext(ctx, make([]byte, 4), make([]byte, 64))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		n, _, err := uc1.WriteMsgUDPContext(context.Background(), []byte{1, 2, 3, 4}, nil, c2.LocalAddr().(*net.UDPAddr))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+		p := make([]byte, 4)
+		n, _, _, addr, err := uc2.ReadMsgUDPContext(context.Background(), p, make([]byte, 64))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+		assert.Equal(t, c1.LocalAddr(), addr)
+	})
+	t.Run("unixgram", func(t *testing.T) {
+		c, err := net.ListenPacket("unixgram", t.TempDir()+"/sock")
+		require.NoError(t, err)
+		pc := WrapPacketConn(c)
+		defer pc.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, _, err = pc.ReadFromContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		n, err := pc.WriteToContext(context.Background(), []byte{1, 2, 3, 4}, pc.LocalAddr())
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+		p := make([]byte, 4)
+		n, _, err = pc.ReadFromContext(context.Background(), p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+	})
+}
+
+func TestConnPipe(t *testing.T) {
+	t.Run("duplex", func(t *testing.T) {
+		c1, c2 := ConnPipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		var _ Conn = c1
+		assert.Equal(t, "pipe", c1.LocalAddr().Network())
+
+		go func() {
+			p := make([]byte, 4)
+			n, err := c2.ReadContext(context.Background(), p)
+			if err == nil {
+				_, _ = c2.WriteContext(context.Background(), p[:n])
+			}
+		}()
+		_, err := c1.WriteContext(context.Background(), []byte{1, 2, 3, 4})
+		require.NoError(t, err)
+		p := make([]byte, 4)
+		n, err := c1.ReadContext(context.Background(), p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, p[:n])
+	})
+	t.Run("addrs", func(t *testing.T) {
+		a := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}
+		b := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 80}
+		c1, c2 := ConnPipe(WithAddrs(a, b))
+		assert.Equal(t, a, c1.LocalAddr())
+		assert.Equal(t, b, c1.RemoteAddr())
+		assert.Equal(t, b, c2.LocalAddr())
+		assert.Equal(t, a, c2.RemoteAddr())
+	})
+	t.Run("cancel", func(t *testing.T) {
+		c1, c2 := ConnPipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err := c1.ReadContext(ctx, make([]byte, 4))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		require.NoError(t, c1.SetDeadline(time.Now().Add(50*time.Millisecond)))
+		_, err = c1.Write([]byte{1})
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	})
+	t.Run("close write", func(t *testing.T) {
+		c1, c2 := ConnPipe(WithBufferSize(16))
+		defer c1.Close()
+		defer c2.Close()
+
+		_, err := c1.Write([]byte("request"))
+		require.NoError(t, err)
+		require.NoError(t, c1.CloseWrite())
+
+		b, err := ReadAll(context.Background(), c2)
+		require.NoError(t, err)
+		assert.Equal(t, "request", string(b))
+
+		// the other direction still works
+		_, err = c2.Write([]byte("response"))
+		require.NoError(t, err)
+		p := make([]byte, 16)
+		n, err := c1.Read(p)
+		assert.NoError(t, err)
+		assert.Equal(t, "response", string(p[:n]))
+	})
+	t.Run("close", func(t *testing.T) {
+		c1, c2 := ConnPipe()
+		require.NoError(t, c1.Close())
+
+		_, err := c1.Read(make([]byte, 4))
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+		_, err = c1.Write([]byte{1})
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+		_, err = c2.Read(make([]byte, 4))
+		assert.ErrorIs(t, err, io.EOF)
+		_, err = c2.Write([]byte{1})
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+}
+
+func TestMemListener(t *testing.T) {
+	t.Run("http", func(t *testing.T) {
+		l := NewMemListener(0)
+		defer l.Close()
+
+		var _ Listener = l
+		srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "hello")
+		})}
+		go func() { _ = srv.Serve(l) }()
+		defer srv.Close()
+
+		client := &http.Client{Transport: &http.Transport{DialContext: l.DialContext}}
+		res, err := client.Get("http://mem/")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(b))
+	})
+	t.Run("backlog", func(t *testing.T) {
+		l := NewMemListener(1, WithBufferSize(16))
+		defer l.Close()
+
+		c1, err := l.DialContext(context.Background(), "", "")
+		require.NoError(t, err)
+		defer c1.Close()
+
+		// the connection is buffered, so it can be written to before it has been accepted
+		_, err = c1.Write([]byte("hello"))
+		require.NoError(t, err)
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		_, err = l.DialContext(ctx, "", "")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		c2, err := l.AcceptContext(context.Background())
+		require.NoError(t, err)
+		defer c2.Close()
+		p := make([]byte, 16)
+		n, err := c2.ReadContext(context.Background(), p)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(p[:n]))
+		assert.Equal(t, l.Addr(), c2.LocalAddr())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err = l.AcceptContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("close", func(t *testing.T) {
+		l := NewMemListener(1)
+
+		queued, err := l.DialContext(context.Background(), "", "")
+		require.NoError(t, err)
+
+		errc := make(chan error, 1)
+		go func() {
+			_, err := l.DialContext(context.Background(), "", "")
+			errc <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, l.Close())
+
+		assert.ErrorIs(t, <-errc, net.ErrClosed)
+		_, err = queued.Read(make([]byte, 1))
+		assert.ErrorIs(t, err, io.EOF)
+		_, err = l.Accept()
+		assert.ErrorIs(t, err, net.ErrClosed)
+		_, err = l.DialContext(context.Background(), "", "")
+		assert.ErrorIs(t, err, net.ErrClosed)
+		assert.ErrorIs(t, l.Close(), net.ErrClosed)
+	})
+	t.Run("race", func(t *testing.T) {
+		// a connection which has been accepted must never be closed by its dialer, even if the listener is closed
+		// before DialContext returns
+		for i := 0; i < 20; i++ {
+			l := NewMemListener(0, WithBufferSize(4))
+
+			type result struct {
+				c   net.Conn
+				err error
+			}
+			dialed := make(chan result, 1)
+			go func() {
+				c, err := l.DialContext(context.Background(), "", "")
+				dialed <- result{c, err}
+			}()
+			time.Sleep(time.Millisecond)
+
+			server, err := l.Accept()
+			require.NoError(t, err)
+			_ = l.Close()
+
+			d := <-dialed
+			require.NoError(t, d.err, "dial failed but the connection was accepted")
+			_, err = server.Write([]byte("x"))
+			assert.NoError(t, err)
+			_, err = d.c.Read(make([]byte, 1))
+			assert.NoError(t, err)
+			_ = d.c.Close()
+			_ = server.Close()
+		}
+	})
+}
@@ -3,19 +3,29 @@ package contextaware
 import (
 	"context"
 	"io"
+	"os"
 	"sync"
+	"time"
 )
 
 // Pipe creates a synchronous in-memory, context-aware pipe. It is modeled after io.Pipe.
 func Pipe() (*PipeReader, *PipeWriter) {
-	p := &pipe{
-		wrCh: make(chan []byte),
-		rdCh: make(chan int),
-		done: make(chan struct{}),
-	}
+	p := newPipe()
 	return &PipeReader{p}, &PipeWriter{p}
 }
 
+func newPipe() *pipe {
+	return &pipe{
+		wrCh:       make(chan pipeBuf),
+		rdCh:       make(chan int),
+		bufCh:      make(chan pipeBuf),
+		fillCh:     make(chan int),
+		done:       make(chan struct{}),
+		rdDeadline: makePipeDeadline(),
+		wrDeadline: makePipeDeadline(),
+	}
+}
+
 // onceError is an object that will only store an error once.
 type onceError struct {
 	sync.Mutex // guards following
@@ -37,15 +47,77 @@ func (a *onceError) Load() error {
 	return a.err
 }
 
+// A pipeBuf is a slice handed from one end of a synchronous pipe to the other, along with the context and deadline
+// of the operation that owns it. The owner waits for the peer to finish with the slice before it returns, so the
+// peer stops using it as soon as the owner is cancelled or its deadline is exceeded.
+type pipeBuf struct {
+	ctx      context.Context
+	deadline chan struct{}
+	b        []byte
+}
+
+// withOwner returns a context for an operation on the slice, which is cancelled when ctx is, when the owner is
+// cancelled or its deadline is exceeded, or when deadline is exceeded.
+func (pb pipeBuf) withOwner(ctx context.Context, deadline chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-pb.ctx.Done():
+		case <-pb.deadline:
+		case <-deadline:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// withPipeDeadline returns a context which is cancelled when ctx is or when deadline is exceeded.
+func withPipeDeadline(ctx context.Context, deadline chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-deadline:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// pipeBufPool holds the scratch buffers used by WriteToContext when the writer uses ReadFromContext.
+var pipeBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, defaultChunkSize)
+		return &b
+	},
+}
+
 type pipe struct {
-	wrMu sync.Mutex // Serializes Write operations
-	wrCh chan []byte
-	rdCh chan int
+	wrMu sync.Mutex   // Serializes Write operations
+	wrCh chan pipeBuf // Writes offered to the reader
+	rdCh chan int     // Bytes consumed from a write
+
+	bufCh  chan pipeBuf // Read buffers offered to ReadFromContext
+	fillCh chan int     // Bytes read into a read buffer
 
 	once sync.Once // Protects closing done
 	done chan struct{}
 	rerr onceError
 	werr onceError
+
+	rdDeadline pipeDeadline
+	wrDeadline pipeDeadline
+}
+
+func (p *pipe) SetReadDeadline(t time.Time) error {
+	p.rdDeadline.set(t)
+	return nil
+}
+
+func (p *pipe) SetWriteDeadline(t time.Time) error {
+	p.wrDeadline.set(t)
+	return nil
 }
 
 func (p *pipe) CloseRead(err error) error {
@@ -67,24 +139,109 @@ func (p *pipe) CloseWrite(err error) error {
 }
 
 func (p *pipe) ReadContext(ctx context.Context, b []byte) (n int, err error) {
-	select {
-	case <-p.done:
+	switch {
+	case isClosedChan(p.done):
 		return 0, p.readCloseError()
-	default:
+	case isClosedChan(p.rdDeadline.wait()):
+		return 0, os.ErrDeadlineExceeded
 	}
 
-	select {
-	case bw := <-p.wrCh:
-		nr := copy(b, bw)
-		p.rdCh <- nr
-		return nr, nil
-	case <-p.done:
-		return 0, p.readCloseError()
-	case <-ctx.Done():
-		return 0, ctx.Err()
+	for {
+		deadline := p.rdDeadline.wait()
+		select {
+		case bw := <-p.wrCh:
+			nr := copy(b, bw.b)
+			p.rdCh <- nr
+			return nr, nil
+		case p.bufCh <- pipeBuf{ctx, deadline, b}:
+			// The writer is reading directly into b. It stops when ctx is cancelled or the deadline is exceeded.
+			nr := <-p.fillCh
+			switch {
+			case nr > 0 || len(b) == 0:
+				return nr, nil
+			case ctx.Err() != nil:
+				return 0, canceled(ctx, "read", 0, nil)
+			case isClosedChan(deadline):
+				return 0, os.ErrDeadlineExceeded
+			}
+		case <-p.done:
+			return 0, p.readCloseError()
+		case <-deadline:
+			return 0, os.ErrDeadlineExceeded
+		case <-ctx.Done():
+			return 0, canceled(ctx, "read", 0, nil)
+		}
 	}
 }
 
+// WriteToContext writes data to w until the write end is closed. Slices written with WriteContext are passed to w
+// directly, without copying them.
+func (p *pipe) WriteToContext(ctx context.Context, w Writer) (n int64, err error) {
+	switch {
+	case isClosedChan(p.done):
+		return 0, p.writeToCloseError()
+	case isClosedChan(p.rdDeadline.wait()):
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	scratch := pipeBufPool.Get().(*[]byte)
+	defer pipeBufPool.Put(scratch)
+
+	for {
+		deadline := p.rdDeadline.wait()
+		select {
+		case bw := <-p.wrCh:
+			wctx, cancel := bw.withOwner(ctx, deadline)
+			nw, err := w.WriteContext(wctx, bw.b)
+			cancel()
+			p.rdCh <- nw
+			n += int64(nw)
+			switch err = pipeDeadlineError(ctx, deadline, err); {
+			case err == nil:
+				if nw < len(bw.b) {
+					return n, io.ErrShortWrite
+				}
+			case isCanceled(err) && ctx.Err() == nil:
+				// the writer was cancelled
+			default:
+				return n, err
+			}
+		case p.bufCh <- pipeBuf{ctx, deadline, *scratch}:
+			// The writer is using ReadFromContext, so data has to be staged. It stops reading into the scratch buffer
+			// when ctx is cancelled or the deadline is exceeded.
+			nr := <-p.fillCh
+			wctx, cancel := withPipeDeadline(ctx, deadline)
+			nw, err := w.WriteContext(wctx, (*scratch)[:nr])
+			cancel()
+			n += int64(nw)
+			if err != nil {
+				return n, pipeDeadlineError(ctx, deadline, err)
+			}
+			if nw < nr {
+				return n, io.ErrShortWrite
+			}
+			if nr == 0 && isClosedChan(deadline) {
+				return n, os.ErrDeadlineExceeded
+			}
+		case <-p.done:
+			return n, p.writeToCloseError()
+		case <-deadline:
+			return n, os.ErrDeadlineExceeded
+		case <-ctx.Done():
+			return n, canceled(ctx, "writeto", int(n), nil)
+		}
+	}
+}
+
+// pipeDeadlineError returns os.ErrDeadlineExceeded if err is the cancellation of an operation which was interrupted
+// by the pipe's deadline rather than by ctx.
+func pipeDeadlineError(ctx context.Context, deadline chan struct{}, err error) error {
+	if isCanceled(err) && ctx.Err() == nil && isClosedChan(deadline) {
+		return os.ErrDeadlineExceeded
+	}
+	return err
+}
+
 func (p *pipe) readCloseError() error {
 	rerr := p.rerr.Load()
 	if werr := p.werr.Load(); rerr == nil && werr != nil {
@@ -93,30 +250,94 @@ func (p *pipe) readCloseError() error {
 	return io.ErrClosedPipe
 }
 
+// writeToCloseError is like readCloseError, but reaching the end of the data isn't an error.
+func (p *pipe) writeToCloseError() error {
+	if err := p.readCloseError(); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
 func (p *pipe) WriteContext(ctx context.Context, b []byte) (n int, err error) {
-	select {
-	case <-p.done:
+	switch {
+	case isClosedChan(p.done):
 		return 0, p.writeCloseError()
+	case isClosedChan(p.wrDeadline.wait()):
+		return 0, os.ErrDeadlineExceeded
 	default:
 		p.wrMu.Lock()
 		defer p.wrMu.Unlock()
 	}
 
 	for once := true; once || len(b) > 0; once = false {
+		deadline := p.wrDeadline.wait()
 		select {
-		case p.wrCh <- b:
+		case p.wrCh <- pipeBuf{ctx, deadline, b}:
+			// If the reader is using WriteToContext, it stops writing b when ctx is cancelled or the deadline is
+			// exceeded.
 			nw := <-p.rdCh
 			b = b[nw:]
 			n += nw
+			switch {
+			case nw > 0 || len(b) == 0:
+			case ctx.Err() != nil:
+				return n, canceled(ctx, "write", n, nil)
+			case isClosedChan(deadline):
+				return n, os.ErrDeadlineExceeded
+			}
 		case <-p.done:
 			return n, p.writeCloseError()
+		case <-deadline:
+			return n, os.ErrDeadlineExceeded
 		case <-ctx.Done():
-			return n, ctx.Err()
+			return n, canceled(ctx, "write", n, nil)
 		}
 	}
 	return n, nil
 }
 
+// ReadFromContext reads data from r until EOF. Data is read directly into the buffers passed to ReadContext, without
+// copying it. While r is being read, a reader whose context is cancelled waits for the read to return, so r should
+// respond to cancellation of the context it's given.
+func (p *pipe) ReadFromContext(ctx context.Context, r Reader) (n int64, err error) {
+	switch {
+	case isClosedChan(p.done):
+		return 0, p.writeCloseError()
+	case isClosedChan(p.wrDeadline.wait()):
+		return 0, os.ErrDeadlineExceeded
+	default:
+		p.wrMu.Lock()
+		defer p.wrMu.Unlock()
+	}
+
+	for {
+		deadline := p.wrDeadline.wait()
+		select {
+		case br := <-p.bufCh:
+			rctx, cancel := br.withOwner(ctx, deadline)
+			nr, err := r.ReadContext(rctx, br.b)
+			cancel()
+			p.fillCh <- nr
+			n += int64(nr)
+			switch err = pipeDeadlineError(ctx, deadline, err); {
+			case err == nil:
+			case err == io.EOF:
+				return n, nil
+			case isCanceled(err) && ctx.Err() == nil:
+				// the reader was cancelled
+			default:
+				return n, err
+			}
+		case <-p.done:
+			return n, p.writeCloseError()
+		case <-deadline:
+			return n, os.ErrDeadlineExceeded
+		case <-ctx.Done():
+			return n, canceled(ctx, "readfrom", int(n), nil)
+		}
+	}
+}
+
 func (p *pipe) writeCloseError() error {
 	werr := p.werr.Load()
 	if rerr := p.rerr.Load(); werr == nil && rerr != nil {
@@ -125,8 +346,20 @@ func (p *pipe) writeCloseError() error {
 	return io.ErrClosedPipe
 }
 
+// pipeImpl is implemented by the synchronous and buffered pipes, and shared by both ends of a pipe.
+type pipeImpl interface {
+	ReadContext(ctx context.Context, b []byte) (n int, err error)
+	WriteContext(ctx context.Context, b []byte) (n int, err error)
+	WriteToContext(ctx context.Context, w Writer) (n int64, err error)
+	ReadFromContext(ctx context.Context, r Reader) (n int64, err error)
+	CloseRead(err error) error
+	CloseWrite(err error) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
+
 type PipeReader struct {
-	p *pipe
+	p pipeImpl
 }
 
 func (pr *PipeReader) Read(data []byte) (n int, err error) {
@@ -137,6 +370,29 @@ func (pr *PipeReader) ReadContext(ctx context.Context, data []byte) (n int, err
 	return pr.p.ReadContext(ctx, data)
 }
 
+// WriteTo writes data to w until the write end is closed, using the background context. It implements io.WriterTo.
+func (pr *PipeReader) WriteTo(w io.Writer) (n int64, err error) {
+	cw, ok := w.(Writer)
+	if !ok {
+		cw = NewWriter(w)
+	}
+	return pr.WriteToContext(context.Background(), cw)
+}
+
+// WriteToContext writes data to w until the write end is closed, or an error occurs. Data is passed to w directly
+// from the writer's slices, or from the pipe's buffer, without an intermediate copy. Closing the write end with
+// io.EOF, which is the default, isn't an error.
+func (pr *PipeReader) WriteToContext(ctx context.Context, w Writer) (n int64, err error) {
+	return pr.p.WriteToContext(ctx, w)
+}
+
+// SetReadDeadline sets the deadline for future and pending reads. A read which times out fails with
+// os.ErrDeadlineExceeded. Like net.Pipe, the deadline can be extended after it has been exceeded, and a zero value
+// means reads will not time out.
+func (pr *PipeReader) SetReadDeadline(t time.Time) error {
+	return pr.p.SetReadDeadline(t)
+}
+
 func (pr *PipeReader) Close() error {
 	return pr.CloseWithError(nil)
 }
@@ -146,7 +402,7 @@ func (pr *PipeReader) CloseWithError(err error) error {
 }
 
 type PipeWriter struct {
-	p *pipe
+	p pipeImpl
 }
 
 func (pw *PipeWriter) Write(data []byte) (n int, err error) {
@@ -157,6 +413,29 @@ func (pw *PipeWriter) WriteContext(ctx context.Context, data []byte) (n int, err
 	return pw.p.WriteContext(ctx, data)
 }
 
+// ReadFrom reads data from r until EOF, using the background context. It implements io.ReaderFrom.
+func (pw *PipeWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	cr, ok := r.(Reader)
+	if !ok {
+		cr = NewReader(r)
+	}
+	return pw.ReadFromContext(context.Background(), cr)
+}
+
+// ReadFromContext reads data from r until EOF, or an error occurs. Data is read directly into the reader's buffer,
+// or into the pipe's buffer, without an intermediate copy. While r is being read, a reader whose context is
+// cancelled waits for the read to return, so r should respond to cancellation of the context it's given.
+func (pw *PipeWriter) ReadFromContext(ctx context.Context, r Reader) (n int64, err error) {
+	return pw.p.ReadFromContext(ctx, r)
+}
+
+// SetWriteDeadline sets the deadline for future and pending writes. A write which times out fails with
+// os.ErrDeadlineExceeded, after possibly writing some of the data. Like net.Pipe, the deadline can be extended after
+// it has been exceeded, and a zero value means writes will not time out.
+func (pw *PipeWriter) SetWriteDeadline(t time.Time) error {
+	return pw.p.SetWriteDeadline(t)
+}
+
 func (pw *PipeWriter) Close() error {
 	return pw.CloseWithError(nil)
 }
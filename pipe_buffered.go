@@ -0,0 +1,333 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// BufferedPipe creates an in-memory, context-aware pipe with an internal buffer of the given size. Writes complete as
+// soon as the data fits in the buffer, and block while it's full. Reads block while the buffer is empty. Once the
+// write end has been closed, the reader receives the remaining buffered data before the close error. If size <= 0,
+// the pipe is synchronous, like Pipe.
+func BufferedPipe(size int) (*PipeReader, *PipeWriter) {
+	if size <= 0 {
+		return Pipe()
+	}
+	p := &bufferedPipe{
+		buf:        make([]byte, size),
+		readable:   make(chan struct{}, 1),
+		writable:   make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		rdDeadline: makePipeDeadline(),
+		wrDeadline: makePipeDeadline(),
+	}
+	return &PipeReader{p}, &PipeWriter{p}
+}
+
+type bufferedPipe struct {
+	rdMu Mutex // Serializes Read operations
+	wrMu Mutex // Serializes Write operations
+
+	mu  sync.Mutex // guards following
+	buf []byte     // ring buffer
+	r   int        // read position in buf
+	n   int        // number of buffered bytes
+
+	readable chan struct{} // signalled when data is added to the buffer
+	writable chan struct{} // signalled when data is removed from the buffer
+
+	once sync.Once // Protects closing done
+	done chan struct{}
+	rerr onceError
+	werr onceError
+
+	rdDeadline pipeDeadline
+	wrDeadline pipeDeadline
+}
+
+// notify signals ch without blocking. Since there is at most one reader and one writer waiting, a single pending
+// signal is enough.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (p *bufferedPipe) SetReadDeadline(t time.Time) error {
+	p.rdDeadline.set(t)
+	return nil
+}
+
+func (p *bufferedPipe) SetWriteDeadline(t time.Time) error {
+	p.wrDeadline.set(t)
+	return nil
+}
+
+func (p *bufferedPipe) CloseRead(err error) error {
+	if err == nil {
+		err = io.ErrClosedPipe
+	}
+	p.rerr.Store(err)
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *bufferedPipe) CloseWrite(err error) error {
+	if err == nil {
+		err = io.EOF
+	}
+	p.werr.Store(err)
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *bufferedPipe) ReadContext(ctx context.Context, b []byte) (n int, err error) {
+	if err := p.rdMu.LockContext(ctx); err != nil {
+		return 0, canceled(ctx, "read", 0, nil)
+	}
+	defer p.rdMu.Unlock()
+
+	for {
+		p.mu.Lock()
+		switch {
+		case p.rerr.Load() != nil:
+			p.mu.Unlock()
+			return 0, io.ErrClosedPipe
+		case isClosedChan(p.rdDeadline.wait()):
+			p.mu.Unlock()
+			return 0, os.ErrDeadlineExceeded
+		case p.n > 0 || len(b) == 0:
+			n = p.read(b)
+			p.mu.Unlock()
+			notify(p.writable)
+			return n, nil
+		}
+		// the buffer has been drained
+		werr := p.werr.Load()
+		p.mu.Unlock()
+		if werr != nil {
+			return 0, werr
+		}
+
+		select {
+		case <-p.readable:
+		case <-p.done:
+		case <-p.rdDeadline.wait():
+		case <-ctx.Done():
+			return 0, canceled(ctx, "read", 0, nil)
+		}
+	}
+}
+
+// read copies buffered data into b. p.mu must be held.
+func (p *bufferedPipe) read(b []byte) (n int) {
+	for n < len(b) && p.n > 0 {
+		end := p.r + p.n
+		if end > len(p.buf) {
+			end = len(p.buf)
+		}
+		nr := copy(b[n:], p.buf[p.r:end])
+		p.r = (p.r + nr) % len(p.buf)
+		p.n -= nr
+		n += nr
+	}
+	return n
+}
+
+// WriteToContext writes buffered data to w until the write end is closed. Data is written directly from the buffer.
+func (p *bufferedPipe) WriteToContext(ctx context.Context, w Writer) (n int64, err error) {
+	if err := p.rdMu.LockContext(ctx); err != nil {
+		return 0, canceled(ctx, "writeto", 0, nil)
+	}
+	defer p.rdMu.Unlock()
+
+	for {
+		p.mu.Lock()
+		deadline := p.rdDeadline.wait()
+		switch {
+		case p.rerr.Load() != nil:
+			p.mu.Unlock()
+			return n, io.ErrClosedPipe
+		case isClosedChan(deadline):
+			p.mu.Unlock()
+			return n, os.ErrDeadlineExceeded
+		case p.n > 0:
+			// The writer only adds data after the buffered bytes, so the region can be used without holding the lock.
+			end := p.r + p.n
+			if end > len(p.buf) {
+				end = len(p.buf)
+			}
+			b := p.buf[p.r:end]
+			p.mu.Unlock()
+
+			wctx, cancel := withPipeDeadline(ctx, deadline)
+			nw, err := w.WriteContext(wctx, b)
+			cancel()
+			if nw < 0 || nw > len(b) {
+				nw = 0
+				if err == nil {
+					err = errInvalidWrite
+				}
+			}
+			p.mu.Lock()
+			p.r = (p.r + nw) % len(p.buf)
+			p.n -= nw
+			p.mu.Unlock()
+			notify(p.writable)
+			n += int64(nw)
+			if err != nil {
+				return n, pipeDeadlineError(ctx, deadline, err)
+			}
+			if nw < len(b) {
+				return n, io.ErrShortWrite
+			}
+			continue
+		}
+		// the buffer has been drained
+		werr := p.werr.Load()
+		p.mu.Unlock()
+		if werr == io.EOF {
+			return n, nil
+		} else if werr != nil {
+			return n, werr
+		}
+
+		select {
+		case <-p.readable:
+		case <-p.done:
+		case <-p.rdDeadline.wait():
+		case <-ctx.Done():
+			return n, canceled(ctx, "writeto", int(n), nil)
+		}
+	}
+}
+
+func (p *bufferedPipe) WriteContext(ctx context.Context, b []byte) (n int, err error) {
+	if err := p.wrMu.LockContext(ctx); err != nil {
+		return 0, canceled(ctx, "write", 0, nil)
+	}
+	defer p.wrMu.Unlock()
+
+	for once := true; once || len(b) > 0; once = false {
+		p.mu.Lock()
+		if p.rerr.Load() != nil || p.werr.Load() != nil {
+			p.mu.Unlock()
+			return n, p.writeCloseError()
+		}
+		if isClosedChan(p.wrDeadline.wait()) {
+			p.mu.Unlock()
+			return n, os.ErrDeadlineExceeded
+		}
+		nw := p.write(b)
+		p.mu.Unlock()
+
+		if nw > 0 {
+			notify(p.readable)
+			b = b[nw:]
+			n += nw
+			continue
+		}
+		if len(b) == 0 {
+			break
+		}
+
+		select {
+		case <-p.writable:
+		case <-p.done:
+		case <-p.wrDeadline.wait():
+		case <-ctx.Done():
+			return n, canceled(ctx, "write", n, nil)
+		}
+	}
+	return n, nil
+}
+
+// ReadFromContext reads data from r until EOF. Data is read directly into the buffer.
+func (p *bufferedPipe) ReadFromContext(ctx context.Context, r Reader) (n int64, err error) {
+	if err := p.wrMu.LockContext(ctx); err != nil {
+		return 0, canceled(ctx, "readfrom", 0, nil)
+	}
+	defer p.wrMu.Unlock()
+
+	for {
+		p.mu.Lock()
+		if p.rerr.Load() != nil || p.werr.Load() != nil {
+			p.mu.Unlock()
+			return n, p.writeCloseError()
+		}
+		deadline := p.wrDeadline.wait()
+		if isClosedChan(deadline) {
+			p.mu.Unlock()
+			return n, os.ErrDeadlineExceeded
+		}
+		if p.n < len(p.buf) {
+			// The reader only removes data before the free region, so it can be used without holding the lock.
+			start := (p.r + p.n) % len(p.buf)
+			end := len(p.buf)
+			if start < p.r {
+				end = p.r
+			}
+			b := p.buf[start:end]
+			p.mu.Unlock()
+
+			rctx, cancel := withPipeDeadline(ctx, deadline)
+			nr, err := r.ReadContext(rctx, b)
+			cancel()
+			if nr < 0 || nr > len(b) {
+				nr = 0
+				if err == nil {
+					err = errInvalidRead
+				}
+			}
+			if nr > 0 {
+				p.mu.Lock()
+				p.n += nr
+				p.mu.Unlock()
+				notify(p.readable)
+				n += int64(nr)
+			}
+			if err == io.EOF {
+				return n, nil
+			} else if err != nil {
+				return n, pipeDeadlineError(ctx, deadline, err)
+			}
+			continue
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-p.writable:
+		case <-p.done:
+		case <-p.wrDeadline.wait():
+		case <-ctx.Done():
+			return n, canceled(ctx, "readfrom", int(n), nil)
+		}
+	}
+}
+
+// write copies as much of b as fits into the buffer. p.mu must be held.
+func (p *bufferedPipe) write(b []byte) (n int) {
+	for n < len(b) && p.n < len(p.buf) {
+		start := (p.r + p.n) % len(p.buf)
+		end := len(p.buf)
+		if start < p.r {
+			end = p.r
+		}
+		nw := copy(p.buf[start:end], b[n:])
+		p.n += nw
+		n += nw
+	}
+	return n
+}
+
+func (p *bufferedPipe) writeCloseError() error {
+	werr := p.werr.Load()
+	if rerr := p.rerr.Load(); werr == nil && rerr != nil {
+		return rerr
+	}
+	return io.ErrClosedPipe
+}
@@ -0,0 +1,133 @@
+package contextaware
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// MessagePipe creates a synchronous in-memory, context-aware pipe which preserves message boundaries. Each write is
+// delivered intact to a single read, so the pipe can be used to pass framed messages between goroutines. Closing,
+// cancellation and deadlines behave as they do for Pipe.
+func MessagePipe() (*MessagePipeReader, *MessagePipeWriter) {
+	p := newPipe()
+	return &MessagePipeReader{p}, &MessagePipeWriter{p}
+}
+
+// readMessage waits for a write and passes it to fn, which reports whether the message was consumed. A message which
+// isn't consumed is offered to the next read, and io.ErrShortBuffer is returned.
+func (p *pipe) readMessage(ctx context.Context, fn func(msg []byte) bool) error {
+	switch {
+	case isClosedChan(p.done):
+		return p.readCloseError()
+	case isClosedChan(p.rdDeadline.wait()):
+		return os.ErrDeadlineExceeded
+	}
+
+	select {
+	case bw := <-p.wrCh:
+		if !fn(bw.b) {
+			p.rdCh <- 0
+			return io.ErrShortBuffer
+		}
+		p.rdCh <- len(bw.b)
+		return nil
+	case <-p.done:
+		return p.readCloseError()
+	case <-p.rdDeadline.wait():
+		return os.ErrDeadlineExceeded
+	case <-ctx.Done():
+		return canceled(ctx, "read", 0, nil)
+	}
+}
+
+// A MessagePipeReader is the read half of a message pipe.
+type MessagePipeReader struct {
+	p *pipe
+}
+
+// Read reads a message into data using the background context.
+func (pr *MessagePipeReader) Read(data []byte) (n int, err error) {
+	return pr.ReadContext(context.Background(), data)
+}
+
+// ReadContext reads a message into data. If the message is larger than data, nothing is read and io.ErrShortBuffer is
+// returned. The message remains in the pipe, so it can be read by a later call with a larger buffer.
+func (pr *MessagePipeReader) ReadContext(ctx context.Context, data []byte) (n int, err error) {
+	err = pr.p.readMessage(ctx, func(msg []byte) bool {
+		if len(msg) > len(data) {
+			return false
+		}
+		n = copy(data, msg)
+		return true
+	})
+	return n, err
+}
+
+// ReadMessage reads a message using the background context.
+func (pr *MessagePipeReader) ReadMessage() ([]byte, error) {
+	return pr.ReadMessageContext(context.Background())
+}
+
+// ReadMessageContext reads a message into a newly allocated slice.
+func (pr *MessagePipeReader) ReadMessageContext(ctx context.Context) (msg []byte, err error) {
+	err = pr.p.readMessage(ctx, func(b []byte) bool {
+		msg = append(make([]byte, 0, len(b)), b...)
+		return true
+	})
+	return msg, err
+}
+
+// SetReadDeadline sets the deadline for future and pending reads. See PipeReader.SetReadDeadline.
+func (pr *MessagePipeReader) SetReadDeadline(t time.Time) error {
+	return pr.p.SetReadDeadline(t)
+}
+
+func (pr *MessagePipeReader) Close() error {
+	return pr.CloseWithError(nil)
+}
+
+func (pr *MessagePipeReader) CloseWithError(err error) error {
+	return pr.p.CloseRead(err)
+}
+
+// A MessagePipeWriter is the write half of a message pipe.
+type MessagePipeWriter struct {
+	p *pipe
+}
+
+// Write writes data as a single message using the background context.
+func (pw *MessagePipeWriter) Write(data []byte) (n int, err error) {
+	return pw.WriteContext(context.Background(), data)
+}
+
+// WriteContext writes data as a single message. It blocks until the message has been read, so either all of data is
+// written, or none of it is.
+func (pw *MessagePipeWriter) WriteContext(ctx context.Context, data []byte) (n int, err error) {
+	return pw.p.WriteContext(ctx, data)
+}
+
+// WriteMessage writes a message using the background context.
+func (pw *MessagePipeWriter) WriteMessage(msg []byte) error {
+	return pw.WriteMessageContext(context.Background(), msg)
+}
+
+// WriteMessageContext writes a message. It blocks until the message has been read.
+func (pw *MessagePipeWriter) WriteMessageContext(ctx context.Context, msg []byte) error {
+	_, err := pw.WriteContext(ctx, msg)
+	return err
+}
+
+// SetWriteDeadline sets the deadline for future and pending writes. See PipeWriter.SetWriteDeadline.
+func (pw *MessagePipeWriter) SetWriteDeadline(t time.Time) error {
+	return pw.p.SetWriteDeadline(t)
+}
+
+func (pw *MessagePipeWriter) Close() error {
+	return pw.CloseWithError(nil)
+}
+
+func (pw *MessagePipeWriter) CloseWithError(err error) error {
+	return pw.p.CloseWrite(err)
+}
@@ -1,11 +1,17 @@
 package contextaware
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPipe(t *testing.T) {
@@ -36,3 +42,365 @@ func TestPipe(t *testing.T) {
 	})
 
 }
+
+func TestBufferedPipe(t *testing.T) {
+	t.Run("buffered", func(t *testing.T) {
+		pr, pw := BufferedPipe(8)
+
+		n, err := pw.WriteContext(context.Background(), []byte{1, 2, 3, 4, 5})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, n)
+
+		ctx, clearTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		n, err = pw.WriteContext(ctx, []byte{6, 7, 8, 9, 10})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 3, n)
+
+		p := make([]byte, 16)
+		n, err = pr.ReadContext(context.Background(), p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, p[:n])
+
+		ctx, clearTimeout = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer clearTimeout()
+		_, err = pr.ReadContext(ctx, p)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("close write", func(t *testing.T) {
+		pr, pw := BufferedPipe(8)
+
+		_, err := pw.Write([]byte{1, 2, 3})
+		require.NoError(t, err)
+		errFailed := errors.New("failed")
+		require.NoError(t, pw.CloseWithError(errFailed))
+
+		_, err = pw.Write([]byte{4})
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+
+		p := make([]byte, 2)
+		n, err := pr.Read(p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2}, p[:n])
+		n, err = pr.Read(p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{3}, p[:n])
+		_, err = pr.Read(p)
+		assert.ErrorIs(t, err, errFailed)
+	})
+	t.Run("close read", func(t *testing.T) {
+		pr, pw := BufferedPipe(2)
+
+		errFailed := errors.New("failed")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = pr.CloseWithError(errFailed)
+		}()
+		n, err := pw.Write([]byte{1, 2, 3, 4})
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, 2, n)
+
+		_, err = pr.Read(make([]byte, 4))
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+	t.Run("stream", func(t *testing.T) {
+		pr, pw := BufferedPipe(7)
+
+		data := make([]byte, 1<<16)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		go func() {
+			rest := data
+			for i := 1; len(rest) > 0; i = i%13 + 1 {
+				if i > len(rest) {
+					i = len(rest)
+				}
+				_, _ = pw.Write(rest[:i])
+				rest = rest[i:]
+			}
+			_ = pw.Close()
+		}()
+
+		var got []byte
+		p := make([]byte, 11)
+		for {
+			n, err := pr.Read(p[:len(got)%11+1])
+			got = append(got, p[:n]...)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+		assert.Equal(t, data, got)
+	})
+}
+
+func TestPipeDeadline(t *testing.T) {
+	pipes := map[string]func() (*PipeReader, *PipeWriter){
+		"sync":     Pipe,
+		"buffered": func() (*PipeReader, *PipeWriter) { return BufferedPipe(4) },
+	}
+	for name, newPipe := range pipes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("read", func(t *testing.T) {
+				pr, pw := newPipe()
+				defer pw.Close()
+
+				require.NoError(t, pr.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+				_, err := pr.Read(make([]byte, 4))
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+				assert.True(t, os.IsTimeout(err))
+
+				// the deadline can be extended
+				require.NoError(t, pr.SetReadDeadline(time.Time{}))
+				go func() { _, _ = pw.Write([]byte{1, 2}) }()
+				p := make([]byte, 4)
+				n, err := pr.Read(p)
+				assert.NoError(t, err)
+				assert.Equal(t, []byte{1, 2}, p[:n])
+			})
+			t.Run("write", func(t *testing.T) {
+				pr, pw := newPipe()
+				defer pr.Close()
+
+				require.NoError(t, pw.SetWriteDeadline(time.Now().Add(50*time.Millisecond)))
+				_, err := pw.Write(make([]byte, 8))
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+				require.NoError(t, pw.SetWriteDeadline(time.Now().Add(-time.Second)))
+				n, err := pw.Write(make([]byte, 1))
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+				assert.Equal(t, 0, n)
+			})
+			t.Run("wrapped", func(t *testing.T) {
+				pr, pw := newPipe()
+				defer pw.Close()
+
+				w := WrapIO(pr)
+				require.NoError(t, w.(interface{ SetReadDeadline(time.Time) error }).SetReadDeadline(time.Now()))
+				_, err := w.(Reader).ReadContext(context.Background(), make([]byte, 4))
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+			})
+		})
+	}
+}
+
+func TestPipeCopy(t *testing.T) {
+	data := make([]byte, 100*1024)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	pipes := map[string]func() (*PipeReader, *PipeWriter){
+		"sync":     Pipe,
+		"buffered": func() (*PipeReader, *PipeWriter) { return BufferedPipe(1000) },
+	}
+	for name, newPipe := range pipes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("write to", func(t *testing.T) {
+				pr, pw := newPipe()
+				go func() {
+					for b := data; len(b) > 0; {
+						chunk := b
+						if len(chunk) > 777 {
+							chunk = chunk[:777]
+						}
+						_, _ = pw.Write(chunk)
+						b = b[len(chunk):]
+					}
+					_ = pw.Close()
+				}()
+
+				var buf bytes.Buffer
+				n, err := Copy(context.Background(), NewWriter(&buf), pr)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(len(data)), n)
+				assert.Equal(t, data, buf.Bytes())
+			})
+			t.Run("read from", func(t *testing.T) {
+				pr, pw := newPipe()
+				go func() {
+					_, _ = Copy(context.Background(), pw, NewReader(bytes.NewReader(data)))
+					_ = pw.Close()
+				}()
+
+				got, err := io.ReadAll(pr)
+				assert.NoError(t, err)
+				assert.Equal(t, data, got)
+			})
+			t.Run("both", func(t *testing.T) {
+				pr, pw := newPipe()
+				go func() {
+					_, _ = io.Copy(pw, bytes.NewReader(data))
+					_ = pw.Close()
+				}()
+
+				var buf bytes.Buffer
+				n, err := io.Copy(&buf, pr)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(len(data)), n)
+				assert.Equal(t, data, buf.Bytes())
+			})
+			t.Run("close error", func(t *testing.T) {
+				pr, pw := newPipe()
+				errBroken := errors.New("broken")
+				go func() {
+					_, _ = pw.Write([]byte("hello"))
+					_ = pw.CloseWithError(errBroken)
+				}()
+
+				var buf bytes.Buffer
+				_, err := pr.WriteTo(&buf)
+				assert.ErrorIs(t, err, errBroken)
+				assert.Equal(t, "hello", buf.String())
+			})
+			t.Run("cancel write to", func(t *testing.T) {
+				pr, pw := newPipe()
+				defer pw.Close()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				_, err := pr.WriteToContext(ctx, NewWriter(io.Discard))
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				var ce *CanceledError
+				require.ErrorAs(t, err, &ce)
+				assert.Equal(t, "writeto", ce.Op)
+			})
+			t.Run("cancel read from", func(t *testing.T) {
+				pr, pw := newPipe()
+				defer pr.Close()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				_, err := pw.ReadFromContext(ctx, NewReader(bytes.NewReader(data)))
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				var ce *CanceledError
+				require.ErrorAs(t, err, &ce)
+				assert.Equal(t, "readfrom", ce.Op)
+			})
+			t.Run("read deadline", func(t *testing.T) {
+				// the read deadline interrupts a WriteTo which is blocked writing
+				pr, pw := newPipe()
+				defer pw.Close()
+				dst, dstw := Pipe()
+				defer dst.Close()
+				go func() { _, _ = pw.Write([]byte("hello")) }()
+
+				time.AfterFunc(50*time.Millisecond, func() { _ = pr.SetReadDeadline(time.Now()) })
+				_, err := pr.WriteToContext(context.Background(), dstw)
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+			})
+			t.Run("write deadline", func(t *testing.T) {
+				// the write deadline interrupts a ReadFrom which is blocked reading
+				pr, pw := newPipe()
+				defer pr.Close()
+				src, srcw := Pipe()
+				defer srcw.Close()
+				go func() { _, _ = pr.Read(make([]byte, 4)) }()
+
+				time.AfterFunc(50*time.Millisecond, func() { _ = pw.SetWriteDeadline(time.Now()) })
+				_, err := pw.ReadFromContext(context.Background(), src)
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+			})
+			t.Run("peer deadlines", func(t *testing.T) {
+				// the deadline of a Write interrupts the WriteTo which is writing its data, and the deadline of a
+				// Read interrupts the ReadFrom which is reading into its buffer
+				pr, pw := newPipe()
+				defer pr.Close()
+				dst, dstw := Pipe()
+				defer dst.Close()
+				go func() { _, _ = pr.WriteToContext(context.Background(), dstw) }()
+
+				require.NoError(t, pw.SetWriteDeadline(time.Now().Add(50*time.Millisecond)))
+				_, err := pw.Write(make([]byte, 2000))
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+				pr, pw = newPipe()
+				defer pw.Close()
+				src, srcw := Pipe()
+				defer srcw.Close()
+				go func() { _, _ = pw.ReadFromContext(context.Background(), src) }()
+
+				require.NoError(t, pr.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+				_, err = pr.Read(make([]byte, 4))
+				assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+			})
+			t.Run("cancel reader", func(t *testing.T) {
+				// the reader's cancellation is passed on to the source of ReadFromContext
+				pr, pw := newPipe()
+				defer pr.Close()
+				src, srcw := Pipe()
+				defer srcw.Close()
+				go func() { _, _ = pw.ReadFromContext(context.Background(), src) }()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				_, err := pr.ReadContext(ctx, make([]byte, 4))
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+				// the writer is still usable
+				go func() { _, _ = srcw.Write([]byte("hi")) }()
+				p := make([]byte, 4)
+				n, err := pr.Read(p)
+				assert.NoError(t, err)
+				assert.Equal(t, "hi", string(p[:n]))
+			})
+		})
+	}
+}
+
+func TestMessagePipe(t *testing.T) {
+	t.Run("boundaries", func(t *testing.T) {
+		pr, pw := MessagePipe()
+		msgs := [][]byte{[]byte("hello"), {}, []byte("world!")}
+		go func() {
+			for _, msg := range msgs {
+				_ = pw.WriteMessage(msg)
+			}
+			_ = pw.Close()
+		}()
+
+		for _, want := range msgs {
+			msg, err := pr.ReadMessage()
+			require.NoError(t, err)
+			assert.Equal(t, want, msg)
+		}
+		_, err := pr.ReadMessage()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("short buffer", func(t *testing.T) {
+		pr, pw := MessagePipe()
+		defer pr.Close()
+		go func() { _ = pw.WriteMessage([]byte("hello")) }()
+
+		p := make([]byte, 8)
+		n, err := pr.Read(p[:4])
+		assert.ErrorIs(t, err, io.ErrShortBuffer)
+		assert.Equal(t, 0, n)
+
+		// the message is still in the pipe
+		n, err = pr.Read(p)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(p[:n]))
+	})
+	t.Run("close read", func(t *testing.T) {
+		pr, pw := MessagePipe()
+		errBroken := errors.New("broken")
+		require.NoError(t, pr.CloseWithError(errBroken))
+
+		err := pw.WriteMessage([]byte("hello"))
+		assert.ErrorIs(t, err, errBroken)
+	})
+	t.Run("cancel", func(t *testing.T) {
+		pr, pw := MessagePipe()
+		defer pw.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := pr.ReadMessageContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		err = pw.WriteMessageContext(ctx, []byte("hello"))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
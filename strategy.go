@@ -0,0 +1,191 @@
+package contextaware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrUnsupportedStrategy is returned by WrapIOWithOptions and the *WithOptions constructors when the requested
+// strategy can't be used with the object being wrapped.
+var ErrUnsupportedStrategy = errors.New("contextaware: unsupported strategy")
+
+// A Strategy describes how a wrapped object is interrupted when a context is cancelled.
+type Strategy int
+
+const (
+	// StrategyNone is reported by StrategyOf for operations a value doesn't support.
+	StrategyNone Strategy = iota
+	// StrategyNative is reported by StrategyOf for objects which implement the context-aware interfaces
+	// themselves, like PipeReader. Such objects are never re-wrapped.
+	StrategyNative
+	// StrategyCheck only checks the context before each operation. Once an operation has started it can't be
+	// interrupted.
+	StrategyCheck
+	// StrategyDeadline interrupts operations by setting a deadline in the past. The object must implement
+	// SetReadDeadline, SetWriteDeadline or SetDeadline. When the strategy is requested explicitly, the deadlines are
+	// cleared once at wrap time to detect objects whose setters return os.ErrNoDeadline, like a regular *os.File,
+	// which are rejected with ErrUnsupportedStrategy. Any deadline already set on the object is lost.
+	StrategyDeadline
+	// StrategyBackground runs reads on a background goroutine, see BackgroundReader. A cancelled read keeps its
+	// goroutine running until the underlying Read returns, unless the object implements io.Closer and is closed. It
+	// only applies to reads: writes use the default strategy.
+	StrategyBackground
+	// StrategyChunked splits operations into bounded chunks and checks the context between them, see
+	// NewChunkedReader and NewChunkedWriter.
+	StrategyChunked
+	// StrategyClose closes the object when a context is cancelled during an operation. The object must implement
+	// io.Closer. Once closed, every subsequent operation fails with ErrClosedByCancel.
+	StrategyClose
+)
+
+// String returns the name of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case StrategyNone:
+		return "none"
+	case StrategyNative:
+		return "native"
+	case StrategyCheck:
+		return "check"
+	case StrategyDeadline:
+		return "deadline"
+	case StrategyBackground:
+		return "background"
+	case StrategyChunked:
+		return "chunked"
+	case StrategyClose:
+		return "close"
+	default:
+		return fmt.Sprintf("Strategy(%d)", int(s))
+	}
+}
+
+// An Option configures how WrapIOWithOptions and the *WithOptions constructors wrap an object.
+type Option func(*options)
+
+type options struct {
+	strategy  Strategy
+	chunkSize int
+
+	// state shared by every interface wrapped for a single object
+	closer           *closeOnCancel
+	deadlinesCreated bool
+	rdDeadline       *deadline
+	wrDeadline       *deadline
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{chunkSize: defaultChunkSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithStrategy sets the strategy used to cancel reads and writes. By default StrategyDeadline is used when the object
+// implements the deadline methods, and StrategyCheck otherwise. If the methods turn out to return os.ErrNoDeadline
+// the object falls back to StrategyCheck, which StrategyOf reports from then on. Requesting StrategyDeadline
+// explicitly instead checks for os.ErrNoDeadline at wrap time by clearing the object's deadlines.
+func WithStrategy(strategy Strategy) Option {
+	return func(o *options) {
+		o.strategy = strategy
+	}
+}
+
+// WithChunkSize sets the size of the chunks used by StrategyChunked.
+func WithChunkSize(chunkSize int) Option {
+	return func(o *options) {
+		if chunkSize > 0 {
+			o.chunkSize = chunkSize
+		}
+	}
+}
+
+// StrategyOf reports the strategies used to cancel reads and writes on v. If v doesn't support reads or writes, the
+// corresponding strategy is StrategyNone.
+func StrategyOf(v interface{}) (read, write Strategy) {
+	if w, ok := v.(interface{ strategies() (Strategy, Strategy) }); ok {
+		return w.strategies()
+	}
+	if r, ok := v.(Reader); ok {
+		read = strategyOf(r)
+	}
+	if w, ok := v.(Writer); ok {
+		write = strategyOf(w)
+	}
+	return read, write
+}
+
+func strategyOf(v interface{}) Strategy {
+	if s, ok := v.(interface{ strategy() Strategy }); ok {
+		return s.strategy()
+	}
+	return StrategyNative
+}
+
+// writeStrategy returns the strategy used for writes. Since StrategyBackground only applies to reads, writes use the
+// default strategy instead.
+func (o *options) writeStrategy() Strategy {
+	if o.strategy == StrategyBackground {
+		return StrategyNone
+	}
+	return o.strategy
+}
+
+// readDeadline returns the deadline used to cancel reads on obj, if it supports deadlines.
+func (o *options) readDeadline(obj interface{}) (*deadline, bool) {
+	o.createDeadlines(obj)
+	return o.rdDeadline, o.rdDeadline != nil
+}
+
+// writeDeadline returns the deadline used to cancel writes on obj, if it supports deadlines.
+func (o *options) writeDeadline(obj interface{}) (*deadline, bool) {
+	o.createDeadlines(obj)
+	return o.wrDeadline, o.wrDeadline != nil
+}
+
+func (o *options) createDeadlines(obj interface{}) {
+	if o.deadlinesCreated {
+		return
+	}
+	o.rdDeadline, o.wrDeadline = newDeadlines(obj)
+	o.deadlinesCreated = true
+
+	if o.strategy == StrategyDeadline {
+		// deadlines were asked for explicitly, so check up front that they're supported rather than failing every
+		// operation later
+		if o.rdDeadline != nil && errors.Is(o.rdDeadline.apply(time.Time{}), os.ErrNoDeadline) {
+			o.rdDeadline = nil
+		}
+		if o.wrDeadline != nil && errors.Is(o.wrDeadline.apply(time.Time{}), os.ErrNoDeadline) {
+			o.wrDeadline = nil
+		}
+		return
+	}
+
+	// objects which turn out not to support deadlines fall back to StrategyCheck
+	for _, d := range []*deadline{o.rdDeadline, o.wrDeadline} {
+		if d != nil {
+			d.setFallback(StrategyCheck, nil)
+		}
+	}
+}
+
+// closeOnCancel returns the closeOnCancel for obj, which must be an io.Closer.
+func (o *options) closeOnCancel(obj interface{}) (*closeOnCancel, bool) {
+	if o.closer == nil {
+		c, ok := obj.(io.Closer)
+		if !ok {
+			return nil, false
+		}
+		o.closer = &closeOnCancel{closer: c}
+	}
+	return o.closer, true
+}
+
+func errUnsupportedStrategy(strategy Strategy, op string, obj interface{}) error {
+	return fmt.Errorf("%w: %s can't be used to %s %T", ErrUnsupportedStrategy, strategy, op, obj)
+}
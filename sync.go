@@ -29,7 +29,7 @@ func (mu *Mutex) LockContext(ctx context.Context) error {
 
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return canceled(ctx, "lock", 0, nil)
 	case <-mu.ch:
 		return nil
 	}
@@ -2,6 +2,7 @@ package contextaware
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,12 @@ func TestMutex(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	assert.ErrorIs(t, context.Canceled, m.LockContext(ctx))
+	err := m.LockContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	var ce *CanceledError
+	if assert.True(t, errors.As(err, &ce)) {
+		assert.Equal(t, "lock", ce.Op)
+	}
 
 	m.Unlock()
 
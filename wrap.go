@@ -1,5 +1,11 @@
 package contextaware
 
+import (
+	"errors"
+	"os"
+	"time"
+)
+
 //go:generate sh -c "go run ./internal/generate-wrap >wrap_gen.go"
 
 // WrapIO wraps an existing type by wrapping any currently supported interfaces with their corresponding context-aware
@@ -9,14 +15,133 @@ package contextaware
 // interfaces:
 //
 //   io.Closer
-//   io.Reader
-//   io.ReaderAt
-//   io.ReaderFrom
+//   io.Reader     => contextaware.Reader
+//   io.ReaderAt   => contextaware.ReaderAt
+//   io.ReaderFrom => contextaware.ReaderFrom
 //   io.Seeker
-//   io.Writer
-//   io.WriterAt
-//   io.WriterTo
+//   io.Writer     => contextaware.Writer
+//   io.WriterAt   => contextaware.WriterAt
+//   io.WriterTo   => contextaware.WriterTo
 //
 func WrapIO(in interface{}) (out interface{}) {
-	return wrapIO(in)
+	out, err := wrapIO(in, newOptions())
+	if err != nil {
+		// the default strategy is supported by every object
+		panic(err)
+	}
+	return out
+}
+
+// WrapIOWithOptions is like WrapIO but allows the cancellation strategy to be chosen explicitly. If the strategy
+// can't be used with `in`, an error wrapping ErrUnsupportedStrategy is returned.
+//
+// The strategy applies to every converted interface, except that StrategyBackground only applies to reads, so writes
+// use the default strategy. Since WriteTo would bypass StrategyBackground, StrategyChunked and StrategyClose, and
+// ReadFrom would bypass StrategyChunked and StrategyClose, io.WriterTo and io.ReaderFrom aren't converted when those
+// strategies are used.
+func WrapIOWithOptions(in interface{}, opts ...Option) (out interface{}, err error) {
+	return wrapIO(in, newOptions(opts...))
+}
+
+// wrapped is embedded in every value returned by WrapIO so that the original object can be recovered.
+type wrapped struct {
+	obj         interface{}
+	opts        *options
+	read, write interface{} // the interfaces which report the strategy of each direction
+}
+
+// add records a converted interface. Each direction's strategy is reported by the first interface converted for it,
+// which is Reader or Writer if the object implements them.
+func (w *wrapped) add(v interface{}) {
+	switch v.(type) {
+	case Reader, ReaderAt, WriterTo:
+		if w.read == nil {
+			w.read = v
+		}
+	}
+	switch v.(type) {
+	case Writer, WriterAt, ReaderFrom:
+		if w.write == nil {
+			w.write = v
+		}
+	}
+}
+
+// strategies reports the strategy of each direction. It's determined on every call, since an object which turns
+// out not to support deadlines switches to its fallback.
+func (w wrapped) strategies() (read, write Strategy) {
+	if w.read != nil {
+		read = strategyOf(w.read)
+	}
+	if w.write != nil {
+		write = strategyOf(w.write)
+	}
+	return read, write
+}
+
+func (w wrapped) unwrap() interface{} {
+	return w.obj
+}
+
+func (w wrapped) readDeadline() (*deadline, bool) {
+	return w.opts.rdDeadline, w.opts.rdDeadline != nil
+}
+
+func (w wrapped) writeDeadline() (*deadline, bool) {
+	return w.opts.wrDeadline, w.opts.wrDeadline != nil
+}
+
+// SetDeadline sets both the read and write deadlines of the wrapped object. See SetReadDeadline and SetWriteDeadline.
+func (w wrapped) SetDeadline(t time.Time) error {
+	rerr, werr := w.SetReadDeadline(t), w.SetWriteDeadline(t)
+	switch {
+	case errors.Is(rerr, os.ErrNoDeadline) && errors.Is(werr, os.ErrNoDeadline):
+		if obj, ok := w.obj.(withSetDeadline); ok {
+			return obj.SetDeadline(t)
+		}
+		return os.ErrNoDeadline
+	case rerr != nil && !errors.Is(rerr, os.ErrNoDeadline):
+		return rerr
+	case werr != nil && !errors.Is(werr, os.ErrNoDeadline):
+		return werr
+	default:
+		return nil
+	}
+}
+
+// SetReadDeadline sets the deadline for future and pending reads on the wrapped object. Since each ReadContext
+// call combines this deadline with its context's deadline, it must be set through the wrapper rather than on the
+// original object. If the object doesn't support deadlines, os.ErrNoDeadline is returned.
+func (w wrapped) SetReadDeadline(t time.Time) error {
+	if d, ok := w.readDeadline(); ok {
+		return d.SetDeadline(t)
+	}
+	if obj, ok := w.obj.(withSetReadDeadline); ok {
+		return obj.SetReadDeadline(t)
+	}
+	return os.ErrNoDeadline
+}
+
+// SetWriteDeadline sets the deadline for future and pending writes on the wrapped object. Since each WriteContext
+// call combines this deadline with its context's deadline, it must be set through the wrapper rather than on the
+// original object. If the object doesn't support deadlines, os.ErrNoDeadline is returned.
+func (w wrapped) SetWriteDeadline(t time.Time) error {
+	if d, ok := w.writeDeadline(); ok {
+		return d.SetDeadline(t)
+	}
+	if obj, ok := w.obj.(withSetWriteDeadline); ok {
+		return obj.SetWriteDeadline(t)
+	}
+	return os.ErrNoDeadline
+}
+
+// unwrap returns the original object that was passed to WrapIO. If obj was not created by WrapIO, it is returned as-is.
+func unwrap(obj interface{}) interface{} {
+	for {
+		w, ok := obj.(interface{ unwrap() interface{} })
+		if !ok {
+			return obj
+		}
+		obj = w.unwrap()
+	}
 }
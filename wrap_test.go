@@ -2,10 +2,15 @@ package contextaware
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"net"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWrapIO(t *testing.T) {
@@ -17,6 +22,11 @@ func TestWrapIO(t *testing.T) {
 		assert.Implements(t, (*io.ReaderFrom)(nil), ctxbuf)
 		assert.Implements(t, (*io.Writer)(nil), ctxbuf)
 		assert.Implements(t, (*io.WriterTo)(nil), ctxbuf)
+		assert.Implements(t, (*Reader)(nil), ctxbuf)
+		assert.Implements(t, (*ReaderFrom)(nil), ctxbuf)
+		assert.Implements(t, (*Writer)(nil), ctxbuf)
+		assert.Implements(t, (*WriterTo)(nil), ctxbuf)
+		assert.Equal(t, &buf, unwrap(ctxbuf))
 	})
 	t.Run("reader", func(t *testing.T) {
 		r := bytes.NewReader([]byte("EXAMPLE"))
@@ -26,5 +36,217 @@ func TestWrapIO(t *testing.T) {
 		assert.Implements(t, (*io.ReaderAt)(nil), ctxr)
 		assert.Implements(t, (*io.Seeker)(nil), ctxr)
 		assert.Implements(t, (*io.WriterTo)(nil), ctxr)
+		assert.Implements(t, (*Reader)(nil), ctxr)
+		assert.Implements(t, (*ReaderAt)(nil), ctxr)
+		assert.Implements(t, (*WriterTo)(nil), ctxr)
+		assert.Equal(t, r, unwrap(ctxr))
 	})
 }
+
+func TestWrapIOWithOptions(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		var buf bytes.Buffer
+		read, write := StrategyOf(WrapIO(&buf))
+		assert.Equal(t, StrategyCheck, read)
+		assert.Equal(t, StrategyCheck, write)
+
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+		read, write = StrategyOf(WrapIO(c1))
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+
+		pr, _ := Pipe()
+		read, write = StrategyOf(WrapIO(pr))
+		assert.Equal(t, StrategyNative, read)
+		assert.Equal(t, StrategyNone, write)
+	})
+	t.Run("deadline", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		out, err := WrapIOWithOptions(c1, WithStrategy(StrategyDeadline))
+		require.NoError(t, err)
+		read, write := StrategyOf(out)
+		assert.Equal(t, StrategyDeadline, read)
+		assert.Equal(t, StrategyDeadline, write)
+
+		var buf bytes.Buffer
+		_, err = WrapIOWithOptions(&buf, WithStrategy(StrategyDeadline))
+		assert.ErrorIs(t, err, ErrUnsupportedStrategy)
+	})
+	t.Run("check", func(t *testing.T) {
+		c1, c2 := net.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		out, err := WrapIOWithOptions(c1, WithStrategy(StrategyCheck))
+		require.NoError(t, err)
+		read, write := StrategyOf(out)
+		assert.Equal(t, StrategyCheck, read)
+		assert.Equal(t, StrategyCheck, write)
+	})
+	t.Run("chunked", func(t *testing.T) {
+		var buf bytes.Buffer
+		out, err := WrapIOWithOptions(&buf, WithStrategy(StrategyChunked), WithChunkSize(16))
+		require.NoError(t, err)
+		read, write := StrategyOf(out)
+		assert.Equal(t, StrategyChunked, read)
+		assert.Equal(t, StrategyChunked, write)
+		assert.Implements(t, (*Reader)(nil), out)
+		assert.Implements(t, (*Writer)(nil), out)
+		_, ok := out.(io.ReaderFrom)
+		assert.False(t, ok)
+		_, ok = out.(io.WriterTo)
+		assert.False(t, ok)
+	})
+	t.Run("background", func(t *testing.T) {
+		// reads use the background strategy and writes the default, so read-write objects like files can be wrapped
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer r.Close()
+		defer w.Close()
+
+		out, err := WrapIOWithOptions(r, WithStrategy(StrategyBackground))
+		require.NoError(t, err)
+		read, write := StrategyOf(out)
+		assert.Equal(t, StrategyBackground, read)
+		assert.Equal(t, StrategyDeadline, write)
+
+		var buf bytes.Buffer
+		out, err = WrapIOWithOptions(&buf, WithStrategy(StrategyBackground))
+		require.NoError(t, err)
+		read, write = StrategyOf(out)
+		assert.Equal(t, StrategyBackground, read)
+		assert.Equal(t, StrategyCheck, write)
+
+		cr, err := NewReaderWithOptions(&buf, WithStrategy(StrategyBackground))
+		require.NoError(t, err)
+		read, write = StrategyOf(cr)
+		assert.Equal(t, StrategyBackground, read)
+		assert.Equal(t, StrategyNone, write)
+
+		cw, err := NewWriterWithOptions(&buf, WithStrategy(StrategyBackground))
+		require.NoError(t, err)
+		read, write = StrategyOf(cw)
+		assert.Equal(t, StrategyNone, read)
+		assert.Equal(t, StrategyCheck, write)
+	})
+}
+
+// fileLike implements every interface converted by WrapIO, but doesn't support deadlines.
+type fileLike struct{}
+
+func (fileLike) Read(p []byte) (int, error)               { return len(p), nil }
+func (fileLike) ReadAt(p []byte, off int64) (int, error)  { return len(p), nil }
+func (fileLike) ReadFrom(r io.Reader) (int64, error)      { return io.Copy(io.Discard, r) }
+func (fileLike) Write(p []byte) (int, error)              { return len(p), nil }
+func (fileLike) WriteAt(p []byte, off int64) (int, error) { return len(p), nil }
+func (fileLike) WriteTo(w io.Writer) (int64, error)       { return 0, nil }
+func (fileLike) Close() error                             { return nil }
+
+// deadlineFileLike is a fileLike which supports deadlines.
+type deadlineFileLike struct {
+	fileLike
+}
+
+func (deadlineFileLike) SetReadDeadline(time.Time) error  { return nil }
+func (deadlineFileLike) SetWriteDeadline(time.Time) error { return nil }
+
+func TestWrapStrategies(t *testing.T) {
+	// unsupported means the strategy is rejected with ErrUnsupportedStrategy, and StrategyNone that the interface
+	// isn't converted.
+	const unsupported Strategy = -1
+
+	type wrapFunc func(obj interface{}, o *options) (interface{}, error)
+	wrappers := []struct {
+		name string
+		wrap wrapFunc
+	}{
+		{"Reader", func(obj interface{}, o *options) (interface{}, error) { return wrapReader(obj.(io.Reader), o) }},
+		{"ReaderAt", func(obj interface{}, o *options) (interface{}, error) { return wrapReaderAt(obj.(io.ReaderAt), o) }},
+		{"ReaderFrom", func(obj interface{}, o *options) (interface{}, error) {
+			return wrapReaderFrom(obj.(io.ReaderFrom), o)
+		}},
+		{"Writer", func(obj interface{}, o *options) (interface{}, error) { return wrapWriter(obj.(io.Writer), o) }},
+		{"WriterAt", func(obj interface{}, o *options) (interface{}, error) { return wrapWriterAt(obj.(io.WriterAt), o) }},
+		{"WriterTo", func(obj interface{}, o *options) (interface{}, error) { return wrapWriterTo(obj.(io.WriterTo), o) }},
+	}
+
+	// the expected strategy for each wrapper, in the order above, for fileLike and deadlineFileLike
+	tests := []struct {
+		strategy         Strategy
+		plain, deadlines [6]Strategy
+	}{
+		{
+			strategy:  StrategyNone,
+			plain:     [6]Strategy{StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck},
+			deadlines: [6]Strategy{StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyDeadline},
+		},
+		{
+			strategy:  StrategyCheck,
+			plain:     [6]Strategy{StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck},
+			deadlines: [6]Strategy{StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck, StrategyCheck},
+		},
+		{
+			strategy:  StrategyDeadline,
+			plain:     [6]Strategy{unsupported, unsupported, unsupported, unsupported, unsupported, unsupported},
+			deadlines: [6]Strategy{StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyDeadline},
+		},
+		{
+			strategy:  StrategyBackground,
+			plain:     [6]Strategy{StrategyBackground, StrategyBackground, StrategyCheck, StrategyCheck, StrategyCheck, StrategyNone},
+			deadlines: [6]Strategy{StrategyBackground, StrategyBackground, StrategyDeadline, StrategyDeadline, StrategyDeadline, StrategyNone},
+		},
+		{
+			strategy:  StrategyChunked,
+			plain:     [6]Strategy{StrategyChunked, StrategyChunked, StrategyNone, StrategyChunked, StrategyChunked, StrategyNone},
+			deadlines: [6]Strategy{StrategyChunked, StrategyChunked, StrategyNone, StrategyChunked, StrategyChunked, StrategyNone},
+		},
+		{
+			strategy:  StrategyClose,
+			plain:     [6]Strategy{StrategyClose, StrategyClose, StrategyNone, StrategyClose, StrategyClose, StrategyNone},
+			deadlines: [6]Strategy{StrategyClose, StrategyClose, StrategyNone, StrategyClose, StrategyClose, StrategyNone},
+		},
+	}
+
+	for _, tt := range tests {
+		objects := []struct {
+			obj      interface{}
+			expected [6]Strategy
+		}{
+			{fileLike{}, tt.plain},
+			{deadlineFileLike{}, tt.deadlines},
+		}
+		for _, object := range objects {
+			for i, w := range wrappers {
+				out, err := w.wrap(object.obj, newOptions(WithStrategy(tt.strategy)))
+				msg := fmt.Sprintf("%s %T with %s", w.name, object.obj, tt.strategy)
+				switch expected := object.expected[i]; expected {
+				case unsupported:
+					assert.ErrorIs(t, err, ErrUnsupportedStrategy, msg)
+				case StrategyNone:
+					assert.NoError(t, err, msg)
+					assert.Nil(t, out, msg)
+				default:
+					if assert.NoError(t, err, msg) {
+						assert.Equal(t, expected, strategyOf(out), msg)
+					}
+				}
+			}
+
+			// StrategyOf reports the strategies of the converted interfaces
+			out, err := WrapIOWithOptions(object.obj, WithStrategy(tt.strategy))
+			if object.expected[0] == unsupported {
+				assert.ErrorIs(t, err, ErrUnsupportedStrategy)
+				continue
+			}
+			require.NoError(t, err)
+			read, write := StrategyOf(out)
+			assert.Equal(t, object.expected[0], read, "read %T with %s", object.obj, tt.strategy)
+			assert.Equal(t, object.expected[3], write, "write %T with %s", object.obj, tt.strategy)
+		}
+	}
+}